@@ -1,6 +1,8 @@
 package main
 
 import (
+	"fmt"
+	"log/slog"
 	"os"
 
 	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/fetcher"
@@ -10,12 +12,41 @@ import (
 )
 
 func main() {
+	log.SetDefault(log.NewLogger(log.NewTerminalHandlerWithLevel(os.Stderr, log.LevelInfo, true)))
+
 	app := &cli.App{
 		Name:    "log-fetcher",
 		Version: "0.0.1",
 		Usage:   "Fetches logs from a given set of chains and stores them in MongoDB",
 		Flags:   flags.Flags,
-		Action:  fetcher.Main,
+		Before: func(ctx *cli.Context) error {
+			var level slog.Level
+			if err := level.UnmarshalText([]byte(ctx.String("log-level"))); err != nil {
+				return fmt.Errorf("invalid log level %q", ctx.String("log-level"))
+			}
+			log.SetDefault(log.NewLogger(log.NewTerminalHandlerWithLevel(os.Stderr, level, true)))
+			return nil
+		},
+		Action: fetcher.Main,
+		Commands: []*cli.Command{
+			{
+				Name:  "config",
+				Usage: "Inspect the networks config",
+				Subcommands: []*cli.Command{
+					{
+						Name:   "check",
+						Usage:  "Validate the networks config and check it against the configured chains",
+						Action: fetcher.CheckConfig,
+					},
+					{
+						Name:   "import",
+						Usage:  "Import contract addresses from forge broadcasts and addresses.json",
+						Flags:  flags.ImportFlags,
+						Action: fetcher.ImportConfig,
+					},
+				},
+			},
+		},
 	}
 
 	if err := app.Run(os.Args); err != nil {
@@ -0,0 +1,154 @@
+package attributes
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+
+	"github.com/base-org/RIP-7755-poc/services/go-filler/bindings"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/ids"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Selectors of the RRC-7755 attributes, see RRC7755Base and RRC7755Outbox.
+var (
+	PrecheckSelector  = [4]byte{0xbe, 0xf8, 0x60, 0x27} // precheck(bytes32)
+	NonceSelector     = [4]byte{0xce, 0x03, 0xfd, 0xab} // nonce(uint256)
+	RewardSelector    = [4]byte{0xa3, 0x62, 0xe5, 0xdb} // reward(bytes32,uint256)
+	DelaySelector     = [4]byte{0x84, 0xf5, 0x50, 0xe0} // delay(uint256,uint256)
+	RequesterSelector = [4]byte{0x3b, 0xd9, 0x4e, 0x4c} // requester(bytes32)
+	L2OracleSelector  = [4]byte{0x7f, 0xf7, 0x24, 0x5a} // l2Oracle(address)
+
+	ShoyuBashiSelector = [4]byte{0xda, 0x07, 0xe1, 0x5d} // shoyuBashi(bytes32), see RRC7755OutboxToHashi
+)
+
+var NativeAsset = common.HexToAddress("0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE")
+
+var ErrNotFound = errors.New("attribute not found")
+
+var (
+	bytes32Type, _ = abi.NewType("bytes32", "", nil)
+	uint256Type, _ = abi.NewType("uint256", "", nil)
+	addressType, _ = abi.NewType("address", "", nil)
+
+	rewardArgs  = abi.Arguments{{Type: bytes32Type}, {Type: uint256Type}}
+	delayArgs   = abi.Arguments{{Type: uint256Type}, {Type: uint256Type}}
+	uint256Args = abi.Arguments{{Type: uint256Type}}
+	bytes32Args = abi.Arguments{{Type: bytes32Type}}
+	addressArgs = abi.Arguments{{Type: addressType}}
+)
+
+// Attributes is an RRC-7755 attributes array. Each attribute is a 4-byte
+// selector followed by its ABI-encoded arguments.
+type Attributes [][]byte
+
+type Reward struct {
+	Asset  [32]byte
+	Amount *big.Int
+}
+
+// AssetAddress returns the reward asset as an EVM address. It reports false if
+// the bytes32 asset does not fit in an address.
+func (r Reward) AssetAddress() (common.Address, bool) {
+	return ids.Bytes32ToAddress(r.Asset)
+}
+
+type Delay struct {
+	FinalityDelaySeconds *big.Int
+	Expiry               *big.Int
+}
+
+// FromRequest builds the RRC-7755 attributes carried implicitly by a
+// RIP-7755 request, so that both request formats can be validated the same way.
+func FromRequest(request *bindings.CrossChainRequest) Attributes {
+	attrs := Attributes{
+		encode(RewardSelector, rewardArgs, ids.AddressToBytes32(request.RewardAsset), orZero(request.RewardAmount)),
+		encode(DelaySelector, delayArgs, orZero(request.FinalityDelaySeconds), orZero(request.Expiry)),
+		encode(NonceSelector, uint256Args, orZero(request.Nonce)),
+		encode(RequesterSelector, bytes32Args, ids.AddressToBytes32(request.Requester)),
+		encode(L2OracleSelector, addressArgs, request.L2Oracle),
+	}
+
+	if request.PrecheckContract != (common.Address{}) {
+		attrs = append(attrs, encode(PrecheckSelector, bytes32Args, ids.AddressToBytes32(request.PrecheckContract)))
+	}
+
+	return attrs
+}
+
+// Locate returns the first attribute with the given selector.
+func (a Attributes) Locate(selector [4]byte) ([]byte, bool) {
+	for _, attr := range a {
+		if len(attr) >= 4 && bytes.Equal(attr[:4], selector[:]) {
+			return attr, true
+		}
+	}
+
+	return nil, false
+}
+
+func (a Attributes) Reward() (Reward, error) {
+	values, err := a.decode(RewardSelector, rewardArgs)
+	if err != nil {
+		return Reward{}, err
+	}
+
+	return Reward{Asset: values[0].([32]byte), Amount: values[1].(*big.Int)}, nil
+}
+
+func (a Attributes) Delay() (Delay, error) {
+	values, err := a.decode(DelaySelector, delayArgs)
+	if err != nil {
+		return Delay{}, err
+	}
+
+	return Delay{FinalityDelaySeconds: values[0].(*big.Int), Expiry: values[1].(*big.Int)}, nil
+}
+
+// Precheck returns the precheck contract. It returns ErrNotFound if the request
+// has no precheck.
+func (a Attributes) Precheck() ([32]byte, error) {
+	values, err := a.decode(PrecheckSelector, bytes32Args)
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	return values[0].([32]byte), nil
+}
+
+// ShoyuBashi returns the ShoyuBashi a Hashi request is proven against. It
+// returns ErrNotFound if the request has none.
+func (a Attributes) ShoyuBashi() ([32]byte, error) {
+	values, err := a.decode(ShoyuBashiSelector, bytes32Args)
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	return values[0].([32]byte), nil
+}
+
+func (a Attributes) decode(selector [4]byte, args abi.Arguments) ([]interface{}, error) {
+	attr, ok := a.Locate(selector)
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return args.Unpack(attr[4:])
+}
+
+func orZero(v *big.Int) *big.Int {
+	if v == nil {
+		return new(big.Int)
+	}
+	return v
+}
+
+func encode(selector [4]byte, args abi.Arguments, values ...interface{}) []byte {
+	packed, err := args.Pack(values...)
+	if err != nil {
+		panic(err)
+	}
+
+	return append(selector[:], packed...)
+}
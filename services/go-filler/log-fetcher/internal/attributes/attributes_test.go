@@ -0,0 +1,89 @@
+package attributes
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/base-org/RIP-7755-poc/services/go-filler/bindings"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/ids"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+var request = &bindings.CrossChainRequest{
+	Requester:            common.HexToAddress("0x1111111111111111111111111111111111111111"),
+	L2Oracle:             common.HexToAddress("0x2222222222222222222222222222222222222222"),
+	RewardAsset:          NativeAsset,
+	RewardAmount:         big.NewInt(2000000000000000000),
+	FinalityDelaySeconds: big.NewInt(3600),
+	Nonce:                big.NewInt(1),
+	Expiry:               big.NewInt(1700000000),
+}
+
+func TestFromRequestReward(t *testing.T) {
+	reward, err := FromRequest(request).Reward()
+
+	assert.NoError(t, err)
+	assert.Equal(t, request.RewardAmount, reward.Amount)
+
+	asset, ok := reward.AssetAddress()
+	assert.True(t, ok)
+	assert.Equal(t, NativeAsset, asset)
+}
+
+func TestFromRequestDelay(t *testing.T) {
+	delay, err := FromRequest(request).Delay()
+
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(3600), delay.FinalityDelaySeconds)
+	assert.Equal(t, big.NewInt(1700000000), delay.Expiry)
+}
+
+func TestFromRequestPrecheckOnlyWhenSet(t *testing.T) {
+	_, ok := FromRequest(request).Locate(PrecheckSelector)
+	assert.False(t, ok)
+
+	withPrecheck := *request
+	withPrecheck.PrecheckContract = common.HexToAddress("0x3333333333333333333333333333333333333333")
+
+	attr, ok := FromRequest(&withPrecheck).Locate(PrecheckSelector)
+	assert.True(t, ok)
+	assert.Equal(t, withPrecheck.PrecheckContract.Bytes(), attr[len(attr)-20:])
+}
+
+func TestPrecheck(t *testing.T) {
+	_, err := FromRequest(request).Precheck()
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	withPrecheck := *request
+	withPrecheck.PrecheckContract = common.HexToAddress("0x3333333333333333333333333333333333333333")
+
+	precheck, err := FromRequest(&withPrecheck).Precheck()
+	assert.NoError(t, err)
+	assert.Equal(t, ids.AddressToBytes32(withPrecheck.PrecheckContract), precheck)
+}
+
+func TestFromRequestHandlesMissingValues(t *testing.T) {
+	reward, err := FromRequest(&bindings.CrossChainRequest{}).Reward()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, reward.Amount.Sign())
+}
+
+func TestRewardNotFound(t *testing.T) {
+	_, err := Attributes{}.Reward()
+
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestShoyuBashi(t *testing.T) {
+	_, err := FromRequest(request).ShoyuBashi()
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	shoyuBashi := common.HexToAddress("0x4444444444444444444444444444444444444444")
+	attrs := append(FromRequest(request), encode(ShoyuBashiSelector, bytes32Args, ids.AddressToBytes32(shoyuBashi)))
+
+	result, err := attrs.ShoyuBashi()
+	assert.NoError(t, err)
+	assert.Equal(t, ids.AddressToBytes32(shoyuBashi), result)
+}
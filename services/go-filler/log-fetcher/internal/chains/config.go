@@ -3,13 +3,19 @@ package chains
 import (
 	"fmt"
 	"math/big"
+	"sort"
+	"time"
 
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/ids"
 	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/provers"
 	"github.com/ethereum/go-ethereum/common"
 )
 
+// NetworksConfig is the chain set the fetcher works with. SupportedChains are
+// the source chains to listen to unless overridden with --supported-chains.
 type NetworksConfig struct {
-	Networks Networks `yaml:"networks"`
+	SupportedChains []string `yaml:"supported-chains"`
+	Networks        Networks `yaml:"networks"`
 }
 type Networks map[string]ChainConfig
 
@@ -19,16 +25,188 @@ type Contracts struct {
 	L2MessagePasser     common.Address `yaml:"l2-message-passer"`
 	Inbox               common.Address `yaml:"inbox"`
 	Outbox              common.Address `yaml:"outbox"`
+	GasPriceOracle      common.Address `yaml:"gas-price-oracle"`
 }
 
+// GasConfig describes the gas model used to price a request. FulfillOverhead
+// and PerCall apply when the chain is the destination, Claim (keyed by prover
+// name) applies when the chain is the source.
+type GasConfig struct {
+	FulfillOverhead uint64            `yaml:"fulfill-overhead"`
+	PerCall         uint64            `yaml:"per-call"`
+	Claim           map[string]uint64 `yaml:"claim"`
+}
+
+// TokenConfig describes an ERC-20 token accepted as a reward on this chain.
+// MinAmount is the smallest reward, in token units, worth filling for.
+type TokenConfig struct {
+	Symbol    string         `yaml:"symbol"`
+	Address   common.Address `yaml:"address"`
+	Decimals  uint8          `yaml:"decimals"`
+	MinAmount *big.Int       `yaml:"min-amount"`
+}
+
+// RouteConfig holds settings for requests from this chain to a given
+// destination chain.
+type RouteConfig struct {
+	MinMarginBps uint64 `yaml:"min-margin-bps"`
+}
+
+// ExposureConfig caps the native value the fulfiller commits on this chain as
+// a destination. MaxOutstanding bounds the call value of all pending requests
+// including the new one, MaxRequestValue that of a single request, and
+// MinBalance is kept in reserve, e.g. for gas.
+type ExposureConfig struct {
+	MaxOutstanding  *big.Int `yaml:"max-outstanding"`
+	MaxRequestValue *big.Int `yaml:"max-request-value"`
+	MinBalance      *big.Int `yaml:"min-balance"`
+}
+
+// Transports a listener can read requests with. TransportAuto subscribes over
+// WebSocket RPCs and polls HTTP ones.
+const (
+	TransportAuto      = "auto"
+	TransportWebSocket = "websocket"
+	TransportPolling   = "polling"
+)
+
+// ListenerConfig tunes how requests are read from this chain as a source.
+// PollInterval and FilterTimeout apply to polling, which fetches logs up to
+// Confirmations blocks behind the head in ranges of at most MaxBlockRange
+// blocks (0 for no limit). Listeners start at StartBlock unless the checkpoint
+// is further along.
+type ListenerConfig struct {
+	PollInterval  time.Duration `yaml:"poll-interval"`
+	FilterTimeout time.Duration `yaml:"filter-timeout"`
+	MaxBlockRange uint64        `yaml:"max-block-range"`
+	Confirmations uint64        `yaml:"confirmations"`
+	StartBlock    uint64        `yaml:"start-block"`
+	Transport     string        `yaml:"transport"`
+}
+
+// DefaultListener holds the listener settings used for anything not
+// configured.
+var DefaultListener = ListenerConfig{
+	PollInterval:  3 * time.Second,
+	FilterTimeout: 10 * time.Second,
+	Transport:     TransportAuto,
+}
+
+// Merge returns l with unset fields filled from d.
+func (l ListenerConfig) Merge(d ListenerConfig) ListenerConfig {
+	if l.PollInterval == 0 {
+		l.PollInterval = d.PollInterval
+	}
+	if l.FilterTimeout == 0 {
+		l.FilterTimeout = d.FilterTimeout
+	}
+	if l.MaxBlockRange == 0 {
+		l.MaxBlockRange = d.MaxBlockRange
+	}
+	if l.Confirmations == 0 {
+		l.Confirmations = d.Confirmations
+	}
+	if l.StartBlock == 0 {
+		l.StartBlock = d.StartBlock
+	}
+	if l.Transport == "" {
+		l.Transport = d.Transport
+	}
+	return l
+}
+
+// RPCConfig tunes calls to this chain's node. Each attempt at a call is cut
+// off after Timeout. Calls that fail for a transient reason, such as a timeout,
+// a dropped connection or a rate limit, are made up to MaxAttempts times,
+// waiting RetryBackoff before the first retry and twice as long before each
+// one after it. After BreakerThreshold calls in a row have failed this way,
+// calls fail fast for BreakerCooldown before one is let through to probe the
+// node.
+type RPCConfig struct {
+	Timeout          time.Duration `yaml:"timeout"`
+	MaxAttempts      int           `yaml:"max-attempts"`
+	RetryBackoff     time.Duration `yaml:"retry-backoff"`
+	BreakerThreshold int           `yaml:"breaker-threshold"`
+	BreakerCooldown  time.Duration `yaml:"breaker-cooldown"`
+}
+
+// DefaultRPC holds the RPC settings used for anything not configured.
+var DefaultRPC = RPCConfig{
+	Timeout:          10 * time.Second,
+	MaxAttempts:      3,
+	RetryBackoff:     250 * time.Millisecond,
+	BreakerThreshold: 5,
+	BreakerCooldown:  30 * time.Second,
+}
+
+// Merge returns r with unset fields filled from d.
+func (r RPCConfig) Merge(d RPCConfig) RPCConfig {
+	if r.Timeout == 0 {
+		r.Timeout = d.Timeout
+	}
+	if r.MaxAttempts == 0 {
+		r.MaxAttempts = d.MaxAttempts
+	}
+	if r.RetryBackoff == 0 {
+		r.RetryBackoff = d.RetryBackoff
+	}
+	if r.BreakerThreshold == 0 {
+		r.BreakerThreshold = d.BreakerThreshold
+	}
+	if r.BreakerCooldown == 0 {
+		r.BreakerCooldown = d.BreakerCooldown
+	}
+	return r
+}
+
+// Kind is the kind of VM a chain runs. Chains are EVM chains unless configured
+// otherwise.
+type Kind string
+
+const (
+	KindEVM    Kind = "evm"
+	KindSolana Kind = "solana"
+)
+
+// SolanaConfig describes a Solana cluster as a destination. ProgramId is the
+// RRC-7755 inbox program from contracts/solana and GenesisHash identifies the
+// cluster. Fulfiller signs fulfillments and pays for them, FulfillFee being
+// what a fulfillment costs it in lamports apart from the call values.
+type SolanaConfig struct {
+	ProgramId   ids.Pubkey `yaml:"program-id"`
+	GenesisHash string     `yaml:"genesis-hash"`
+	Fulfiller   ids.Pubkey `yaml:"fulfiller"`
+	FulfillFee  uint64     `yaml:"fulfill-fee"`
+}
+
+// DefaultSolanaFulfillFee covers one signature and the rent-exempt balance of
+// the 48-byte fulfillment info account fulfill creates.
+const DefaultSolanaFulfillFee uint64 = 5_000 + 1_224_960
+
 type ChainConfig struct {
-	ChainId            *big.Int                  `yaml:"chain-id"`
-	ProverContracts    map[string]common.Address `yaml:"prover-contracts"`
-	RpcUrl             string                    `yaml:"rpc-url"`
-	L2Oracle           common.Address            `yaml:"l2-oracle"`
-	L2OracleStorageKey string                    `yaml:"l2-oracle-storage-key"`
-	Contracts          *Contracts                `yaml:"contracts"`
-	TargetProver       provers.Prover            `yaml:"target-prover"`
+	Kind               Kind                       `yaml:"kind"`
+	Solana             *SolanaConfig              `yaml:"solana"`
+	ChainId            *big.Int                   `yaml:"chain-id"`
+	ProverContracts    map[string]common.Address  `yaml:"prover-contracts"`
+	RpcUrl             string                     `yaml:"rpc-url"`
+	RPC                RPCConfig                  `yaml:"rpc"`
+	L2Oracle           common.Address             `yaml:"l2-oracle"`
+	L2OracleStorageKey string                     `yaml:"l2-oracle-storage-key"`
+	Contracts          *Contracts                 `yaml:"contracts"`
+	TargetProver       provers.Prover             `yaml:"target-prover"`
+	Gas                GasConfig                  `yaml:"gas"`
+	Routes             map[string]RouteConfig     `yaml:"routes"`
+	RewardTokens       []TokenConfig              `yaml:"reward-tokens"`
+	NativeSymbol       string                     `yaml:"native-symbol"`
+	ProverLatency      map[string]provers.Latency `yaml:"prover-latency"`
+	Fulfiller          common.Address             `yaml:"fulfiller"`
+	CallPolicy         CallPolicy                 `yaml:"call-policy"`
+	ExposesL1State     *bool                      `yaml:"exposes-l1-state"`
+	SharesStateWithL1  *bool                      `yaml:"shares-state-with-l1"`
+	MockProofs         bool                       `yaml:"mock-proofs"`
+	ShoyuBashi         common.Address             `yaml:"shoyu-bashi"`
+	Exposure           ExposureConfig             `yaml:"exposure"`
+	Listener           ListenerConfig             `yaml:"listener"`
 }
 
 func (n *Networks) GetChainConfig(chainId *big.Int) (*ChainConfig, error) {
@@ -39,3 +217,140 @@ func (n *Networks) GetChainConfig(chainId *big.Int) (*ChainConfig, error) {
 
 	return &chainConfig, nil
 }
+
+// Find returns the chain with the given CAIP-2 id.
+func (n Networks) Find(id ids.ChainID) (*ChainConfig, bool) {
+	for _, chain := range n {
+		if chain.ID() == id {
+			return &chain, true
+		}
+	}
+
+	return nil, false
+}
+
+// Destinations returns the keys, in order, of the chains that requests from
+// srcChainId can be filled on: every other chain with a target prover, and
+// Solana chains.
+func (n Networks) Destinations(srcChainId string) []string {
+	var keys []string
+	for key, chain := range n {
+		if key == srcChainId {
+			continue
+		}
+		if chain.ChainKind() == KindSolana || chain.TargetProver != "" && chain.TargetProver != provers.NilProver {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// ChainKind returns the chain's kind, defaulting to EVM.
+func (c *ChainConfig) ChainKind() Kind {
+	if c.Kind == "" {
+		return KindEVM
+	}
+	return c.Kind
+}
+
+// ID returns the chain's CAIP-2 id. Solana clusters are identified by their
+// genesis hash, other chains by their chain id.
+func (c *ChainConfig) ID() ids.ChainID {
+	if c.ChainKind() == KindSolana && c.Solana != nil {
+		return ids.SolanaChain(c.Solana.GenesisHash)
+	}
+	return ids.EVMChain(c.ChainId)
+}
+
+// Account returns the CAIP-10 id of an address on this chain.
+func (c *ChainConfig) Account(addr common.Address) ids.Account {
+	return ids.Account{Chain: c.ID(), Address: addr.Hex()}
+}
+
+// SelectProver returns the prover for requests from c to dst. The
+// destination's target prover needs the source chain to expose L1 state and the
+// destination to share state with L1, other routes are proven through Hashi.
+// Both flags default to true. Solana doesn't settle to L1, so requests to it
+// are always proven through Hashi.
+func (c *ChainConfig) SelectProver(dst *ChainConfig) provers.Prover {
+	if dst.ChainKind() == KindSolana {
+		return provers.HashiProver
+	}
+	if orTrue(c.ExposesL1State) && orTrue(dst.SharesStateWithL1) {
+		return dst.TargetProver
+	}
+	return provers.HashiProver
+}
+
+// MocksProofs reports whether requests from c to dst are proven against a
+// mocked L1 state root, as on a devnet, so that the destination state can be
+// proven as soon as it lands instead of once L1 has finalized it. Like the TS
+// filler's devnet mode, this only applies to routes not proven through Hashi.
+func (c *ChainConfig) MocksProofs(dst *ChainConfig) bool {
+	return c.MockProofs && c.SelectProver(dst) != provers.HashiProver
+}
+
+func orTrue(b *bool) bool {
+	return b == nil || *b
+}
+
+// NativeAssetSymbol returns the price symbol of the chain's native asset,
+// defaulting to SOL on Solana and ETH elsewhere.
+func (c *ChainConfig) NativeAssetSymbol() string {
+	switch {
+	case c.NativeSymbol != "":
+		return c.NativeSymbol
+	case c.ChainKind() == KindSolana:
+		return "SOL"
+	default:
+		return "ETH"
+	}
+}
+
+// NativeAssetDecimals returns the decimals of the chain's native asset, 9 for
+// lamports and 18 for wei.
+func (c *ChainConfig) NativeAssetDecimals() uint8 {
+	if c.ChainKind() == KindSolana {
+		return 9
+	}
+	return 18
+}
+
+// SolanaFulfillFee returns the lamports a fulfillment costs on a Solana chain.
+func (c *ChainConfig) SolanaFulfillFee() uint64 {
+	if c.Solana == nil || c.Solana.FulfillFee == 0 {
+		return DefaultSolanaFulfillFee
+	}
+	return c.Solana.FulfillFee
+}
+
+// GetProverLatency returns the latency estimates for filling requests from
+// this chain that are proven with prover, falling back to the prover's
+// defaults for anything not configured.
+func (c *ChainConfig) GetProverLatency(prover provers.Prover) provers.Latency {
+	return c.ProverLatency[string(prover)].Merge(provers.DefaultLatency[prover])
+}
+
+func (c *ChainConfig) GetRewardToken(addr common.Address) (*TokenConfig, bool) {
+	for i := range c.RewardTokens {
+		if c.RewardTokens[i].Address == addr {
+			return &c.RewardTokens[i], true
+		}
+	}
+
+	return nil, false
+}
+
+// Normalize scales an amount in token units to 18 decimals so that rewards in
+// different tokens can be compared to wei amounts.
+func (t *TokenConfig) Normalize(amount *big.Int) *big.Int {
+	if t.Decimals <= 18 {
+		scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(18-t.Decimals)), nil)
+		return new(big.Int).Mul(amount, scale)
+	}
+
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(t.Decimals-18)), nil)
+	return new(big.Int).Div(amount, scale)
+}
@@ -4,7 +4,9 @@ import (
 	"math/big"
 	"reflect"
 	"testing"
+	"time"
 
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/ids"
 	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/provers"
 	"github.com/ethereum/go-ethereum/common"
 )
@@ -73,3 +75,177 @@ func TestGetChainConfig_UnknownChain(t *testing.T) {
 		t.Errorf("GetChainConfig(%d) = %+v, want error", unknownChainID, result)
 	}
 }
+
+func TestGetRewardToken(t *testing.T) {
+	usdc := common.HexToAddress("0x036CbD53842c5426634e7929541eC2318f3dCF7e")
+	cfg := &ChainConfig{
+		RewardTokens: []TokenConfig{
+			{Symbol: "USDC", Address: usdc, Decimals: 6},
+		},
+	}
+
+	token, ok := cfg.GetRewardToken(usdc)
+	if !ok || token.Symbol != "USDC" || token.Decimals != 6 {
+		t.Errorf("GetRewardToken(%s) = %+v, %v, want USDC", usdc, token, ok)
+	}
+
+	if _, ok := cfg.GetRewardToken(common.HexToAddress("0x1")); ok {
+		t.Errorf("GetRewardToken(0x1) found token, want none")
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	testCases := []struct {
+		decimals uint8
+		amount   *big.Int
+		expected *big.Int
+	}{
+		{18, big.NewInt(5), big.NewInt(5)},
+		{6, big.NewInt(1_500_000), big.NewInt(1_500_000_000_000_000_000)},
+		{20, big.NewInt(500), big.NewInt(5)},
+	}
+
+	for _, tc := range testCases {
+		token := &TokenConfig{Decimals: tc.decimals}
+		if result := token.Normalize(tc.amount); result.Cmp(tc.expected) != 0 {
+			t.Errorf("Normalize(%s) with %d decimals = %s, want %s", tc.amount, tc.decimals, result, tc.expected)
+		}
+	}
+}
+
+func TestGetProverLatency(t *testing.T) {
+	cfg := &ChainConfig{
+		ProverLatency: map[string]provers.Latency{
+			string(provers.OPStackProver): {ProofSeconds: 600},
+		},
+	}
+
+	expected := provers.Latency{FulfillSeconds: 300, ProofSeconds: 600, ClaimSeconds: 300}
+	if result := cfg.GetProverLatency(provers.OPStackProver); result != expected {
+		t.Errorf("GetProverLatency(OPStack) = %+v, want %+v", result, expected)
+	}
+
+	if result := cfg.GetProverLatency(provers.HashiProver); result != provers.DefaultLatency[provers.HashiProver] {
+		t.Errorf("GetProverLatency(Hashi) = %+v, want defaults", result)
+	}
+}
+
+func TestSelectProver(t *testing.T) {
+	no := false
+	dst := &ChainConfig{TargetProver: provers.OPStackProver}
+
+	testCases := []struct {
+		name     string
+		src      *ChainConfig
+		dst      *ChainConfig
+		expected provers.Prover
+	}{
+		{"defaults", &ChainConfig{}, dst, provers.OPStackProver},
+		{"source doesn't expose L1 state", &ChainConfig{ExposesL1State: &no}, dst, provers.HashiProver},
+		{"destination doesn't share state with L1", &ChainConfig{}, &ChainConfig{TargetProver: provers.OPStackProver, SharesStateWithL1: &no}, provers.HashiProver},
+		{"solana destination", &ChainConfig{}, &ChainConfig{Kind: KindSolana}, provers.HashiProver},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if result := tc.src.SelectProver(tc.dst); result != tc.expected {
+				t.Errorf("SelectProver() = %s, want %s", result, tc.expected)
+			}
+		})
+	}
+}
+
+func TestMocksProofs(t *testing.T) {
+	no := false
+	devnet := &ChainConfig{MockProofs: true}
+	dst := &ChainConfig{TargetProver: provers.OPStackProver}
+
+	if !devnet.MocksProofs(dst) {
+		t.Errorf("MocksProofs() = false on a devnet chain, want true")
+	}
+	if (&ChainConfig{}).MocksProofs(dst) {
+		t.Errorf("MocksProofs() = true by default, want false")
+	}
+	// Hashi proofs don't go through an L1 state root
+	if devnet.MocksProofs(&ChainConfig{TargetProver: provers.OPStackProver, SharesStateWithL1: &no}) {
+		t.Errorf("MocksProofs() = true on a Hashi route, want false")
+	}
+}
+
+func TestDestinations(t *testing.T) {
+	networks := Networks{
+		"421614":   {TargetProver: provers.ArbitrumProver},
+		"84532":    {TargetProver: provers.OPStackProver},
+		"11155420": {TargetProver: provers.OPStackProver},
+		"11155111": {TargetProver: provers.NilProver},
+		"103":      {Kind: KindSolana},
+	}
+
+	expected := []string{"103", "11155420", "84532"}
+	if result := networks.Destinations("421614"); !reflect.DeepEqual(result, expected) {
+		t.Errorf("Destinations() = %v, want %v", result, expected)
+	}
+}
+
+func TestFind(t *testing.T) {
+	networks := Networks{
+		"421614": {ChainId: big.NewInt(421614)},
+		"84532":  {ChainId: big.NewInt(84532)},
+	}
+
+	chain, ok := networks.Find(ids.ChainID{Namespace: ids.EIP155, Reference: "84532"})
+	if !ok || chain.ChainId.Int64() != 84532 {
+		t.Errorf("Find(eip155:84532) = %v, %v", chain, ok)
+	}
+	if _, ok := networks.Find(ids.ChainID{Namespace: "solana", Reference: "84532"}); ok {
+		t.Errorf("Find(solana:84532) found a chain")
+	}
+}
+
+func TestAccount(t *testing.T) {
+	chain := &ChainConfig{ChainId: big.NewInt(84532)}
+	addr := common.HexToAddress("0xab16a96D359eC26a11e2C2b3d8f8B8942d5Bfcdb")
+
+	if result := chain.Account(addr).String(); result != "eip155:84532:0xab16a96D359eC26a11e2C2b3d8f8B8942d5Bfcdb" {
+		t.Errorf("Account() = %s", result)
+	}
+}
+
+func TestSolanaChain(t *testing.T) {
+	chain := &ChainConfig{Kind: KindSolana, ChainId: big.NewInt(103), Solana: &SolanaConfig{GenesisHash: "EtWTRABZaYq6iMfeYKouRu166VU2xqa1wcaWoxPkrZBG"}}
+
+	if result := chain.ID().String(); result != "solana:EtWTRABZaYq6iMfeYKouRu166VU2xqa1" {
+		t.Errorf("ID() = %s", result)
+	}
+	if chain.NativeAssetSymbol() != "SOL" || chain.NativeAssetDecimals() != 9 {
+		t.Errorf("native asset = %s with %d decimals, want SOL with 9", chain.NativeAssetSymbol(), chain.NativeAssetDecimals())
+	}
+	if chain.SolanaFulfillFee() != DefaultSolanaFulfillFee {
+		t.Errorf("SolanaFulfillFee() = %d, want %d", chain.SolanaFulfillFee(), DefaultSolanaFulfillFee)
+	}
+
+	evm := &ChainConfig{ChainId: big.NewInt(84532)}
+	if evm.ChainKind() != KindEVM || evm.NativeAssetSymbol() != "ETH" || evm.NativeAssetDecimals() != 18 {
+		t.Errorf("EVM chain = %s, %s with %d decimals", evm.ChainKind(), evm.NativeAssetSymbol(), evm.NativeAssetDecimals())
+	}
+}
+
+func TestListenerConfigMerge(t *testing.T) {
+	result := ListenerConfig{PollInterval: 12 * time.Second, Confirmations: 2}.Merge(DefaultListener)
+
+	expected := ListenerConfig{PollInterval: 12 * time.Second, FilterTimeout: 10 * time.Second, Confirmations: 2, Transport: TransportAuto}
+	if result != expected {
+		t.Errorf("Merge() = %+v, want %+v", result, expected)
+	}
+}
+
+func TestRPCConfigMerge(t *testing.T) {
+	result := RPCConfig{Timeout: 3 * time.Second, MaxAttempts: 1}.Merge(DefaultRPC)
+
+	expected := DefaultRPC
+	expected.Timeout = 3 * time.Second
+	expected.MaxAttempts = 1
+	if result != expected {
+		t.Errorf("Merge() = %+v, want %+v", result, expected)
+	}
+}
@@ -0,0 +1,93 @@
+package chains
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// CallPolicy restricts the calls we're willing to execute on this chain when it
+// is the destination. A call is denied if it matches any Deny rule, and if Allow
+// is set it must match one of its rules. MaxCalls and MaxCallValue are ignored
+// when unset.
+type CallPolicy struct {
+	MaxCalls     int        `yaml:"max-calls"`
+	MaxCallValue *big.Int   `yaml:"max-call-value"`
+	Allow        []CallRule `yaml:"allow"`
+	Deny         []CallRule `yaml:"deny"`
+}
+
+// CallRule matches calls to Target, or to any target if unset, whose function
+// selector is one of Selectors, or any selector if empty.
+type CallRule struct {
+	Target    common.Address `yaml:"target"`
+	Selectors []Selector     `yaml:"selectors"`
+}
+
+// Selector is a 4-byte function selector, written as hex in config files.
+type Selector [4]byte
+
+func (s *Selector) UnmarshalText(text []byte) error {
+	b, err := hexutil.Decode(string(text))
+	if err != nil {
+		return fmt.Errorf("invalid selector %q: %v", text, err)
+	}
+	if len(b) != 4 {
+		return fmt.Errorf("invalid selector %q: want 4 bytes, got %d", text, len(b))
+	}
+
+	copy(s[:], b)
+	return nil
+}
+
+func (s Selector) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s Selector) String() string {
+	return hexutil.Encode(s[:])
+}
+
+// Matches reports whether the rule covers a call to target with the given
+// calldata. Calls without a full selector, such as plain transfers, only match
+// rules that don't list selectors.
+func (r CallRule) Matches(target common.Address, data []byte) bool {
+	if r.Target != (common.Address{}) && r.Target != target {
+		return false
+	}
+	if len(r.Selectors) == 0 {
+		return true
+	}
+	if len(data) < 4 {
+		return false
+	}
+
+	for _, s := range r.Selectors {
+		if s == Selector(data[:4]) {
+			return true
+		}
+	}
+	return false
+}
+
+// Permits reports whether the policy's allow and deny lists let a call to
+// target with the given calldata through.
+func (p *CallPolicy) Permits(target common.Address, data []byte) bool {
+	for _, r := range p.Deny {
+		if r.Matches(target, data) {
+			return false
+		}
+	}
+	if len(p.Allow) == 0 {
+		return true
+	}
+
+	for _, r := range p.Allow {
+		if r.Matches(target, data) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package chains
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gopkg.in/yaml.v2"
+)
+
+var (
+	token    = common.HexToAddress("0x036CbD53842c5426634e7929541eC2318f3dCF7e")
+	other    = common.HexToAddress("0x1234567890123456789012345678901234567890")
+	transfer = []byte{0xa9, 0x05, 0x9c, 0xbb, 0x00}
+	approve  = []byte{0x09, 0x5e, 0xa7, 0xb3, 0x00}
+)
+
+func TestCallPolicyUnmarshal(t *testing.T) {
+	var policy CallPolicy
+	err := yaml.Unmarshal([]byte(`
+max-calls: 3
+max-call-value: 1000000000000000000
+allow:
+  - target: 0x036CbD53842c5426634e7929541eC2318f3dCF7e
+    selectors: ["0xa9059cbb"]
+`), &policy)
+	if err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+
+	if policy.MaxCalls != 3 || policy.MaxCallValue.String() != "1000000000000000000" {
+		t.Errorf("Unmarshal = %+v, want max-calls 3 and max-call-value 1e18", policy)
+	}
+	if len(policy.Allow) != 1 || policy.Allow[0].Target != token || policy.Allow[0].Selectors[0].String() != "0xa9059cbb" {
+		t.Errorf("Unmarshal allow = %+v, want transfer on %s", policy.Allow, token)
+	}
+}
+
+func TestSelectorUnmarshalInvalid(t *testing.T) {
+	var policy CallPolicy
+	if err := yaml.Unmarshal([]byte(`deny: [{selectors: ["0xa9059c"]}]`), &policy); err == nil {
+		t.Errorf("Unmarshal of a 3-byte selector succeeded, want error")
+	}
+}
+
+func TestCallPolicyPermits(t *testing.T) {
+	testCases := []struct {
+		name     string
+		policy   CallPolicy
+		target   common.Address
+		data     []byte
+		expected bool
+	}{
+		{"empty policy", CallPolicy{}, other, nil, true},
+		{"allowed selector", CallPolicy{Allow: []CallRule{{Target: token, Selectors: []Selector{{0xa9, 0x05, 0x9c, 0xbb}}}}}, token, transfer, true},
+		{"other selector", CallPolicy{Allow: []CallRule{{Target: token, Selectors: []Selector{{0xa9, 0x05, 0x9c, 0xbb}}}}}, token, approve, false},
+		{"other target", CallPolicy{Allow: []CallRule{{Target: token}}}, other, transfer, false},
+		{"plain transfer against selector rule", CallPolicy{Allow: []CallRule{{Selectors: []Selector{{0xa9, 0x05, 0x9c, 0xbb}}}}}, other, nil, false},
+		{"denied target", CallPolicy{Deny: []CallRule{{Target: other}}}, other, nil, false},
+		{"denied selector on any target", CallPolicy{Deny: []CallRule{{Selectors: []Selector{{0x09, 0x5e, 0xa7, 0xb3}}}}}, token, approve, false},
+		{"deny wins over allow", CallPolicy{Allow: []CallRule{{Target: token}}, Deny: []CallRule{{Selectors: []Selector{{0x09, 0x5e, 0xa7, 0xb3}}}}}, token, approve, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if result := tc.policy.Permits(tc.target, tc.data); result != tc.expected {
+				t.Errorf("Permits(%s, %x) = %v, want %v", tc.target, tc.data, result, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package clients
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrCircuitOpen = errors.New("circuit open")
+
+// breaker stops calls to a node that keeps failing. It opens after threshold
+// failures in a row and rejects calls until cooldown has passed. It then lets
+// a single call through, which closes it again if it succeeds.
+type breaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openedAt  time.Time
+	now       func() time.Time
+
+	// probe identifies the call probing the node while the breaker is half
+	// open, 0 if there is none. Calls let through before the breaker opened
+	// can't end the probe.
+	probe  uint64
+	probes uint64
+}
+
+func newBreaker(threshold int, cooldown time.Duration) *breaker {
+	return &breaker{threshold: threshold, cooldown: cooldown, now: time.Now}
+}
+
+// breakers are shared by every client of the same chain, so that the
+// validator, listener and simulator back off from its node together. They are
+// dropped when the pool closes the chain's clients, so that a redialed node
+// starts out closed.
+var (
+	breakersMu sync.Mutex
+	breakers   = make(map[string]*breaker)
+)
+
+// breakerFor returns the breaker of the chain's node, updated to the given
+// settings.
+func breakerFor(chain string, threshold int, cooldown time.Duration) *breaker {
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+
+	b, ok := breakers[chain]
+	if !ok {
+		b = newBreaker(threshold, cooldown)
+		breakers[chain] = b
+		return b
+	}
+
+	b.mu.Lock()
+	b.threshold, b.cooldown = threshold, cooldown
+	b.mu.Unlock()
+	return b
+}
+
+func dropBreakers(chains ...string) {
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+
+	for _, chain := range chains {
+		delete(breakers, chain)
+	}
+}
+
+// allow reports whether a call may go through. A call let through to probe
+// the node gets a non-zero probe, which it passes back when it ends.
+func (b *breaker) allow() (probe uint64, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.threshold {
+		return 0, true
+	}
+	if b.probe != 0 || b.now().Sub(b.openedAt) < b.cooldown {
+		return 0, false
+	}
+	b.probes++
+	b.probe = b.probes
+	return b.probe, true
+}
+
+// success records a call the node answered, which closes the breaker.
+func (b *breaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.probe = 0
+}
+
+// release ends a call that says nothing about the node, because the caller
+// gave up on it. If it was the probe, another call may probe the node.
+func (b *breaker) release(probe uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.end(probe)
+}
+
+// failure records a failed call and reports whether it opened the breaker.
+func (b *breaker) failure(probe uint64) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	wasOpen := b.failures >= b.threshold
+	b.failures++
+	b.end(probe)
+	if b.failures >= b.threshold {
+		b.openedAt = b.now()
+		return !wasOpen
+	}
+	return false
+}
+
+func (b *breaker) end(probe uint64) {
+	if probe != 0 && probe == b.probe {
+		b.probe = 0
+	}
+}
@@ -0,0 +1,70 @@
+package clients
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBreaker(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	b := newBreaker(2, time.Minute)
+	b.now = func() time.Time { return now }
+
+	assert.False(t, b.failure(0))
+	assert.True(t, allowed(b))
+	assert.True(t, b.failure(0))
+	assert.False(t, allowed(b))
+
+	now = now.Add(time.Minute)
+	probe, ok := b.allow()
+	assert.True(t, ok, "probe after cooldown")
+	assert.NotZero(t, probe)
+	assert.False(t, allowed(b), "one probe at a time")
+
+	assert.False(t, b.failure(probe), "failed probe keeps it open")
+	assert.False(t, allowed(b))
+
+	now = now.Add(time.Minute)
+	assert.True(t, allowed(b))
+	b.success()
+	assert.True(t, allowed(b))
+	assert.True(t, allowed(b))
+}
+
+func TestBreakerProbeOwnership(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	b := newBreaker(1, time.Minute)
+	b.now = func() time.Time { return now }
+
+	assert.True(t, b.failure(0))
+	now = now.Add(time.Minute)
+	probe, ok := b.allow()
+	assert.True(t, ok)
+
+	// calls let through before the breaker opened don't end the probe
+	b.release(0)
+	assert.False(t, allowed(b))
+
+	b.release(probe)
+	assert.True(t, allowed(b), "probe after the prober gave up")
+}
+
+func TestBreakerFor(t *testing.T) {
+	b := breakerFor("900100", 2, time.Minute)
+
+	same := breakerFor("900100", 3, time.Second)
+
+	assert.Same(t, b, same)
+	assert.Equal(t, 3, same.threshold)
+	assert.NotSame(t, b, breakerFor("900101", 2, time.Minute))
+
+	dropBreakers("900100")
+	assert.NotSame(t, b, breakerFor("900100", 2, time.Minute))
+}
+
+func allowed(b *breaker) bool {
+	_, ok := b.allow()
+	return ok
+}
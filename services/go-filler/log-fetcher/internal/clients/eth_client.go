@@ -1,18 +1,152 @@
 package clients
 
 import (
+	"context"
+	"math/big"
+
 	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/chains"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
-	"github.com/ethereum/go-ethereum/log"
 )
 
-func GetEthClient(cfg *chains.ChainConfig) (*ethclient.Client, error) {
-	client, err := ethclient.Dial(cfg.RpcUrl)
+// EthClient is the part of ethclient.Client the filler uses, with every call
+// going through RPC. It implements bind.ContractBackend, so bindings can use
+// it too.
+type EthClient struct {
+	rpc *RPC
+	eth *ethclient.Client
+}
+
+func GetEthClient(cfg *chains.ChainConfig) (*EthClient, error) {
+	r, err := Dial(cfg)
 	if err != nil {
 		return nil, err
 	}
 
-	log.Info("Connected to client", "url", cfg.RpcUrl)
+	return &EthClient{rpc: r, eth: ethclient.NewClient(r.client)}, nil
+}
+
+// RPC returns the underlying connection, for calls ethclient doesn't cover.
+func (c *EthClient) RPC() *RPC {
+	return c.rpc
+}
+
+func (c *EthClient) ChainID(ctx context.Context) (id *big.Int, err error) {
+	err = c.rpc.do(ctx, "eth_chainId", func(ctx context.Context) error {
+		id, err = c.eth.ChainID(ctx)
+		return err
+	})
+	return id, err
+}
+
+func (c *EthClient) BlockNumber(ctx context.Context) (number uint64, err error) {
+	err = c.rpc.do(ctx, "eth_blockNumber", func(ctx context.Context) error {
+		number, err = c.eth.BlockNumber(ctx)
+		return err
+	})
+	return number, err
+}
+
+func (c *EthClient) HeaderByNumber(ctx context.Context, number *big.Int) (header *types.Header, err error) {
+	err = c.rpc.do(ctx, "eth_getBlockByNumber", func(ctx context.Context) error {
+		header, err = c.eth.HeaderByNumber(ctx, number)
+		return err
+	})
+	return header, err
+}
+
+func (c *EthClient) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (balance *big.Int, err error) {
+	err = c.rpc.do(ctx, "eth_getBalance", func(ctx context.Context) error {
+		balance, err = c.eth.BalanceAt(ctx, account, blockNumber)
+		return err
+	})
+	return balance, err
+}
+
+func (c *EthClient) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) (code []byte, err error) {
+	err = c.rpc.do(ctx, "eth_getCode", func(ctx context.Context) error {
+		code, err = c.eth.CodeAt(ctx, account, blockNumber)
+		return err
+	})
+	return code, err
+}
+
+func (c *EthClient) PendingCodeAt(ctx context.Context, account common.Address) (code []byte, err error) {
+	err = c.rpc.do(ctx, "eth_getCode", func(ctx context.Context) error {
+		code, err = c.eth.PendingCodeAt(ctx, account)
+		return err
+	})
+	return code, err
+}
+
+func (c *EthClient) PendingNonceAt(ctx context.Context, account common.Address) (nonce uint64, err error) {
+	err = c.rpc.do(ctx, "eth_getTransactionCount", func(ctx context.Context) error {
+		nonce, err = c.eth.PendingNonceAt(ctx, account)
+		return err
+	})
+	return nonce, err
+}
+
+func (c *EthClient) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) (result []byte, err error) {
+	err = c.rpc.do(ctx, "eth_call", func(ctx context.Context) error {
+		result, err = c.eth.CallContract(ctx, msg, blockNumber)
+		return err
+	})
+	return result, err
+}
+
+func (c *EthClient) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (gas uint64, err error) {
+	err = c.rpc.do(ctx, "eth_estimateGas", func(ctx context.Context) error {
+		gas, err = c.eth.EstimateGas(ctx, msg)
+		return err
+	})
+	return gas, err
+}
+
+func (c *EthClient) SuggestGasPrice(ctx context.Context) (price *big.Int, err error) {
+	err = c.rpc.do(ctx, "eth_gasPrice", func(ctx context.Context) error {
+		price, err = c.eth.SuggestGasPrice(ctx)
+		return err
+	})
+	return price, err
+}
+
+func (c *EthClient) SuggestGasTipCap(ctx context.Context) (tip *big.Int, err error) {
+	err = c.rpc.do(ctx, "eth_maxPriorityFeePerGas", func(ctx context.Context) error {
+		tip, err = c.eth.SuggestGasTipCap(ctx)
+		return err
+	})
+	return tip, err
+}
+
+// SendTransaction is never retried, a transaction that timed out may still
+// have been broadcast.
+func (c *EthClient) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	return c.rpc.once(ctx, "eth_sendRawTransaction", func(ctx context.Context) error {
+		return c.eth.SendTransaction(ctx, tx)
+	})
+}
+
+func (c *EthClient) FilterLogs(ctx context.Context, q ethereum.FilterQuery) (logs []types.Log, err error) {
+	err = c.rpc.do(ctx, "eth_getLogs", func(ctx context.Context) error {
+		logs, err = c.eth.FilterLogs(ctx, q)
+		return err
+	})
+	return logs, err
+}
+
+// SubscribeFilterLogs subscribes to logs. Only the subscription request goes
+// through RPC, the subscription itself lives on after it returns.
+func (c *EthClient) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (sub ethereum.Subscription, err error) {
+	err = c.rpc.do(ctx, "eth_subscribe", func(ctx context.Context) error {
+		sub, err = c.eth.SubscribeFilterLogs(ctx, q, ch)
+		return err
+	})
+	return sub, err
+}
 
-	return client, nil
+func (c *EthClient) Close() {
+	c.rpc.Close()
 }
@@ -0,0 +1,93 @@
+package clients
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/chains"
+)
+
+// Pool holds one client per chain, dialed on first use and shared by every
+// stage that talks to the chain's node. Clients are keyed by chain id, so a
+// chain whose rpc-url changes must be closed to be dialed again.
+type Pool struct {
+	mu     sync.Mutex
+	eth    map[string]*EthClient
+	solana map[string]*SolanaClient
+}
+
+func NewPool() *Pool {
+	return &Pool{eth: make(map[string]*EthClient), solana: make(map[string]*SolanaClient)}
+}
+
+// Eth returns the client of an EVM chain.
+func (p *Pool) Eth(cfg *chains.ChainConfig) (*EthClient, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := cfg.ChainId.String()
+	if c, ok := p.eth[key]; ok {
+		return c, nil
+	}
+
+	c, err := GetEthClient(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get eth client for chain %s: %v", key, err)
+	}
+	p.eth[key] = c
+
+	return c, nil
+}
+
+// Solana returns the client of a Solana chain.
+func (p *Pool) Solana(cfg *chains.ChainConfig) (*SolanaClient, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := cfg.ChainId.String()
+	if c, ok := p.solana[key]; ok {
+		return c, nil
+	}
+
+	c, err := GetSolanaClient(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get solana client for chain %s: %v", key, err)
+	}
+	p.solana[key] = c
+
+	return c, nil
+}
+
+// Close closes the clients of the given chains and drops their breakers. They
+// are dialed again on their next use.
+func (p *Pool) Close(chainIds ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	dropBreakers(chainIds...)
+	for _, key := range chainIds {
+		if c, ok := p.eth[key]; ok {
+			c.Close()
+			delete(p.eth, key)
+		}
+		if c, ok := p.solana[key]; ok {
+			c.Close()
+			delete(p.solana, key)
+		}
+	}
+}
+
+// CloseAll closes every client in the pool.
+func (p *Pool) CloseAll() {
+	p.mu.Lock()
+	keys := make([]string, 0, len(p.eth)+len(p.solana))
+	for key := range p.eth {
+		keys = append(keys, key)
+	}
+	for key := range p.solana {
+		keys = append(keys, key)
+	}
+	p.mu.Unlock()
+
+	p.Close(keys...)
+}
@@ -0,0 +1,39 @@
+package clients
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/chains"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPool(t *testing.T) {
+	pool := NewPool()
+	cfg := &chains.ChainConfig{ChainId: big.NewInt(84532), RpcUrl: "http://localhost:8545"}
+
+	client, err := pool.Eth(cfg)
+	assert.NoError(t, err)
+	same, _ := pool.Eth(cfg)
+	assert.Same(t, client, same)
+
+	solana, err := pool.Solana(&chains.ChainConfig{ChainId: big.NewInt(103), RpcUrl: "http://localhost:8899"})
+	assert.NoError(t, err)
+
+	pool.Close("84532")
+	redialed, err := pool.Eth(cfg)
+	assert.NoError(t, err)
+	assert.NotSame(t, client, redialed)
+	assert.NotSame(t, client.rpc.breaker, redialed.rpc.breaker, "breaker dropped on close")
+
+	pool.CloseAll()
+	assert.Empty(t, pool.eth)
+	assert.Empty(t, pool.solana)
+	assert.NotNil(t, solana)
+}
+
+func TestPoolDialError(t *testing.T) {
+	_, err := NewPool().Eth(&chains.ChainConfig{ChainId: big.NewInt(84532), RpcUrl: "invalid-url"})
+
+	assert.ErrorContains(t, err, "failed to get eth client for chain 84532")
+}
@@ -0,0 +1,45 @@
+package clients
+
+import (
+	"net/url"
+	"strings"
+)
+
+const redacted = "REDACTED"
+
+// RedactURL strips an RPC URL down to its scheme and host, since providers put
+// API keys in the path, query or user info. Everything we log or report about
+// a node goes through it.
+func RedactURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return redacted
+	}
+
+	redactedURL := u.Scheme + "://" + u.Host
+	if u.User != nil || (u.Path != "" && u.Path != "/") || u.RawQuery != "" {
+		redactedURL += "/" + redacted
+	}
+	return redactedURL
+}
+
+// RedactError hides rawURL in err, which transport errors often quote. The
+// original error can still be matched with errors.Is and errors.As.
+func RedactError(err error, rawURL string) error {
+	if err == nil || rawURL == "" || !strings.Contains(err.Error(), rawURL) {
+		return err
+	}
+	return &redactedError{err: err, msg: strings.ReplaceAll(err.Error(), rawURL, RedactURL(rawURL))}
+}
+
+type redactedError struct {
+	err error
+	msg string
+}
+
+func (e *redactedError) Error() string { return e.msg }
+func (e *redactedError) Unwrap() error { return e.err }
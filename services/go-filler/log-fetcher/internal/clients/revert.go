@@ -0,0 +1,51 @@
+package clients
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// JSON-RPC error code nodes use for a reverted eth_call.
+const executionRevertedCode = 3
+
+// RevertReason reports whether err returned by eth_call is an execution
+// revert, as opposed to a transport or node error, along with the decoded
+// Error(string) or Panic(uint256) reason. Custom errors are returned as their
+// hex-encoded revert data.
+func RevertReason(err error) (string, bool) {
+	if err == nil {
+		return "", false
+	}
+
+	var rpcErr rpc.Error
+	reverted := errors.As(err, &rpcErr) && rpcErr.ErrorCode() == executionRevertedCode
+	reverted = reverted || strings.Contains(err.Error(), "execution reverted")
+	if !reverted {
+		return "", false
+	}
+
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		return err.Error(), true
+	}
+
+	hexData, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return err.Error(), true
+	}
+
+	data, decodeErr := hexutil.Decode(hexData)
+	if decodeErr != nil {
+		return err.Error(), true
+	}
+
+	if reason, unpackErr := abi.UnpackRevert(data); unpackErr == nil {
+		return reason, true
+	}
+
+	return hexData, true
+}
@@ -0,0 +1,52 @@
+package clients
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type revertError struct {
+	code int
+	data interface{}
+}
+
+func (e *revertError) Error() string          { return "execution reverted" }
+func (e *revertError) ErrorCode() int         { return e.code }
+func (e *revertError) ErrorData() interface{} { return e.data }
+
+// Error(string) with reason "not allowed"
+const notAllowedRevert = "0x08c379a00000000000000000000000000000000000000000000000000000000000000020000000000000000000000000000000000000000000000000000000000000000b6e6f7420616c6c6f776564000000000000000000000000000000000000000000"
+
+func TestRevertReason(t *testing.T) {
+	t.Run("error string", func(t *testing.T) {
+		reason, ok := RevertReason(&revertError{code: 3, data: notAllowedRevert})
+
+		assert.True(t, ok)
+		assert.Equal(t, "not allowed", reason)
+	})
+
+	t.Run("custom error", func(t *testing.T) {
+		data := hexutil.Encode([]byte{0xde, 0xad, 0xbe, 0xef})
+		reason, ok := RevertReason(fmt.Errorf("eth_call: %w", &revertError{code: 3, data: data}))
+
+		assert.True(t, ok)
+		assert.Equal(t, data, reason)
+	})
+
+	t.Run("revert without data", func(t *testing.T) {
+		reason, ok := RevertReason(errors.New("execution reverted"))
+
+		assert.True(t, ok)
+		assert.Equal(t, "execution reverted", reason)
+	})
+
+	t.Run("transport error", func(t *testing.T) {
+		_, ok := RevertReason(errors.New("connection refused"))
+
+		assert.False(t, ok)
+	})
+}
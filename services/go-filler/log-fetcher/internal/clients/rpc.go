@@ -0,0 +1,207 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/chains"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/metrics"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// JSON-RPC error code providers use when a request is over its limits.
+const limitExceededCode = -32005
+
+// errorClass tells how a failed call should be handled.
+type errorClass int
+
+const (
+	// permanent errors are answers from the node, such as reverts or invalid
+	// params, which a retry won't change.
+	permanent errorClass = iota
+	// transient errors are timeouts, dropped connections and server errors.
+	transient
+	// rateLimited errors are the provider asking us to slow down.
+	rateLimited
+)
+
+// RPC is a JSON-RPC connection to a chain's node. Every call is cut off after
+// the chain's rpc timeout, retried if it failed for a transient reason and
+// counted in metrics by method. Calls fail fast with ErrCircuitOpen while the
+// node is considered down. The node's URL is only ever logged or returned in
+// errors redacted.
+type RPC struct {
+	client   *rpc.Client
+	rawURL   string
+	url      string
+	chain    string
+	settings chains.RPCConfig
+	breaker  *breaker
+}
+
+// Dial connects to cfg's node.
+func Dial(cfg *chains.ChainConfig) (*RPC, error) {
+	settings := cfg.RPC.Merge(chains.DefaultRPC)
+	chain := chainLabel(cfg)
+
+	ctx, cancel := context.WithTimeout(context.Background(), settings.Timeout)
+	defer cancel()
+
+	client, err := rpc.DialContext(ctx, cfg.RpcUrl)
+	if err != nil {
+		metrics.Counter("rpc/" + chain + "/dial/errors").Inc(1)
+		return nil, RedactError(err, cfg.RpcUrl)
+	}
+
+	r := &RPC{
+		client:   client,
+		rawURL:   cfg.RpcUrl,
+		url:      RedactURL(cfg.RpcUrl),
+		chain:    chain,
+		settings: settings,
+		breaker:  breakerFor(chain, settings.BreakerThreshold, settings.BreakerCooldown),
+	}
+	log.Info("Connected to client", "chain", chain, "url", r.url)
+
+	return r, nil
+}
+
+// CallContext performs a JSON-RPC call, see rpc.Client.CallContext.
+func (r *RPC) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	return r.do(ctx, method, func(ctx context.Context) error {
+		return r.client.CallContext(ctx, result, method, args...)
+	})
+}
+
+func (r *RPC) Close() {
+	r.client.Close()
+}
+
+// do runs call, the RPC method named method, with the connection's timeout,
+// retry and breaker settings.
+func (r *RPC) do(ctx context.Context, method string, call func(context.Context) error) error {
+	prefix := "rpc/" + r.chain + "/" + method + "/"
+
+	probe, ok := r.breaker.allow()
+	if !ok {
+		metrics.Counter("rpc/" + r.chain + "/circuit_open").Inc(1)
+		return fmt.Errorf("%s on chain %s: %w", method, r.chain, ErrCircuitOpen)
+	}
+
+	backoff := r.settings.RetryBackoff
+	for attempt := 1; ; attempt++ {
+		callCtx, cancel := context.WithTimeout(ctx, r.settings.Timeout)
+		start := time.Now()
+		err := call(callCtx)
+		cancel()
+
+		metrics.Counter(prefix + "requests").Inc(1)
+		metrics.Counter(prefix + "duration_ms").Inc(time.Since(start).Milliseconds())
+		if err == nil {
+			r.breaker.success()
+			return nil
+		}
+		err = RedactError(err, r.rawURL)
+
+		class := classify(err)
+		if class == permanent || ctx.Err() != nil {
+			// the node answered, or we gave up on it, neither says it's down
+			if class == permanent {
+				r.breaker.success()
+			} else {
+				r.breaker.release(probe)
+			}
+			metrics.Counter(prefix + "errors").Inc(1)
+			return err
+		}
+		if class == rateLimited {
+			metrics.Counter(prefix + "rate_limited").Inc(1)
+		}
+
+		if attempt >= r.settings.MaxAttempts {
+			metrics.Counter(prefix + "errors").Inc(1)
+			if r.breaker.failure(probe) {
+				log.Warn("RPC node failing, pausing calls", "chain", r.chain, "url", r.url, "cooldown", r.settings.BreakerCooldown, "error", err)
+			}
+			return err
+		}
+
+		// providers measure their limits over seconds, back off harder
+		delay := backoff
+		if class == rateLimited {
+			delay *= 4
+		}
+		log.Debug("Retrying RPC call", "chain", r.chain, "url", r.url, "method", method, "attempt", attempt, "delay", delay, "error", err)
+		metrics.Counter(prefix + "retries").Inc(1)
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			r.breaker.release(probe)
+			metrics.Counter(prefix + "errors").Inc(1)
+			return err
+		}
+		backoff *= 2
+	}
+}
+
+// once is do without retries, for calls that mustn't be repeated.
+func (r *RPC) once(ctx context.Context, method string, call func(context.Context) error) error {
+	single := *r
+	single.settings.MaxAttempts = 1
+	return single.do(ctx, method, call)
+}
+
+// classify tells whether a failed call is worth retrying.
+func classify(err error) errorClass {
+	if _, reverted := RevertReason(err); reverted {
+		return permanent
+	}
+	if errors.Is(err, ethereum.NotFound) || errors.Is(err, context.Canceled) || errors.Is(err, rpc.ErrClientQuit) {
+		return permanent
+	}
+
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		switch {
+		case httpErr.StatusCode == http.StatusTooManyRequests:
+			return rateLimited
+		case httpErr.StatusCode >= 500:
+			return transient
+		default:
+			return permanent
+		}
+	}
+
+	msg := strings.ToLower(err.Error())
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		if rpcErr.ErrorCode() == limitExceededCode || strings.Contains(msg, "rate limit") || strings.Contains(msg, "too many requests") {
+			return rateLimited
+		}
+		return permanent
+	}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+		return permanent
+	}
+
+	// anything else failed on the way to or from the node
+	return transient
+}
+
+func chainLabel(cfg *chains.ChainConfig) string {
+	if cfg.ChainId == nil {
+		return "unknown"
+	}
+	return cfg.ChainId.String()
+}
@@ -0,0 +1,201 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/chains"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/metrics"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+// flakyNode answers eth_blockNumber, first with the given responses, one per
+// request, and with 0x1 from then on. It counts the requests it gets.
+func flakyNode(t *testing.T, calls *int32, responses ...func(w http.ResponseWriter, id json.RawMessage)) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Id json.RawMessage `json:"id"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		n := atomic.AddInt32(calls, 1)
+		if int(n) <= len(responses) {
+			responses[n-1](w, req.Id)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(req.Id) + `,"result":"0x1"}`))
+	}))
+}
+
+func status(code int) func(http.ResponseWriter, json.RawMessage) {
+	return func(w http.ResponseWriter, _ json.RawMessage) {
+		w.WriteHeader(code)
+	}
+}
+
+func rpcError(code int, message string) func(http.ResponseWriter, json.RawMessage) {
+	return func(w http.ResponseWriter, id json.RawMessage) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"error":{"code":%d,"message":%q}}`, id, code, message)
+	}
+}
+
+func slow(d time.Duration) func(http.ResponseWriter, json.RawMessage) {
+	return func(w http.ResponseWriter, _ json.RawMessage) {
+		time.Sleep(d)
+	}
+}
+
+func testChain(chainId int64, url string) *chains.ChainConfig {
+	return &chains.ChainConfig{
+		ChainId: big.NewInt(chainId),
+		RpcUrl:  url,
+		RPC:     chains.RPCConfig{Timeout: 100 * time.Millisecond, RetryBackoff: time.Millisecond, BreakerThreshold: 2, BreakerCooldown: time.Minute},
+	}
+}
+
+// counter returns how much the counter name has grown since it was called.
+func counter(name string) func() int64 {
+	start := metrics.Counter(name).Snapshot().Count()
+	return func() int64 {
+		return metrics.Counter(name).Snapshot().Count() - start
+	}
+}
+
+func TestRPCRetriesTransientErrors(t *testing.T) {
+	var calls int32
+	requests := counter("rpc/900001/eth_blockNumber/requests")
+	retries := counter("rpc/900001/eth_blockNumber/retries")
+	failures := counter("rpc/900001/eth_blockNumber/errors")
+	server := flakyNode(t, &calls, status(http.StatusServiceUnavailable), slow(200*time.Millisecond))
+	defer server.Close()
+
+	client, err := GetEthClient(testChain(900001, server.URL))
+	assert.NoError(t, err)
+
+	number, err := client.BlockNumber(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), number)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	assert.Equal(t, int64(3), requests())
+	assert.Equal(t, int64(2), retries())
+	assert.Equal(t, int64(0), failures())
+}
+
+func TestRPCRetriesRateLimits(t *testing.T) {
+	var calls int32
+	limited := counter("rpc/900002/eth_blockNumber/rate_limited")
+	server := flakyNode(t, &calls, status(http.StatusTooManyRequests), rpcError(limitExceededCode, "daily request count exceeded"))
+	defer server.Close()
+
+	client, err := GetEthClient(testChain(900002, server.URL))
+	assert.NoError(t, err)
+
+	_, err = client.BlockNumber(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), limited())
+}
+
+func TestRPCDoesntRetryPermanentErrors(t *testing.T) {
+	var calls int32
+	failures := counter("rpc/900003/eth_blockNumber/errors")
+	server := flakyNode(t, &calls, rpcError(-32602, "invalid params"))
+	defer server.Close()
+
+	client, err := GetEthClient(testChain(900003, server.URL))
+	assert.NoError(t, err)
+
+	_, err = client.BlockNumber(context.Background())
+
+	assert.ErrorContains(t, err, "invalid params")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	assert.Equal(t, int64(1), failures())
+}
+
+func TestRPCOpensBreaker(t *testing.T) {
+	var calls int32
+	circuitOpen := counter("rpc/900004/circuit_open")
+	server := flakyNode(t, &calls,
+		status(http.StatusBadGateway), status(http.StatusBadGateway), status(http.StatusBadGateway),
+		status(http.StatusBadGateway), status(http.StatusBadGateway), status(http.StatusBadGateway),
+	)
+	defer server.Close()
+
+	client, err := GetEthClient(testChain(900004, server.URL))
+	assert.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err = client.BlockNumber(context.Background())
+		assert.Error(t, err)
+	}
+	_, err = client.BlockNumber(context.Background())
+
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, int32(6), atomic.LoadInt32(&calls))
+	assert.Equal(t, int64(1), circuitOpen())
+
+	// the breaker is shared by every client of the chain
+	other, err := GetSolanaClient(testChain(900004, server.URL))
+	assert.NoError(t, err)
+	_, err = other.GenesisHash(context.Background())
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+}
+
+func TestRPCRedactsURL(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL + "/v2/secret-key"
+	server.Close()
+
+	client, err := GetEthClient(testChain(900005, url))
+	assert.NoError(t, err)
+
+	_, err = client.BlockNumber(context.Background())
+
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), "secret-key")
+	assert.Contains(t, err.Error(), "/REDACTED")
+}
+
+type jsonRPCError struct {
+	code    int
+	message string
+}
+
+func (e *jsonRPCError) Error() string  { return e.message }
+func (e *jsonRPCError) ErrorCode() int { return e.code }
+
+func TestClassify(t *testing.T) {
+	testCases := []struct {
+		err      error
+		expected errorClass
+	}{
+		{rpc.HTTPError{StatusCode: http.StatusTooManyRequests}, rateLimited},
+		{rpc.HTTPError{StatusCode: http.StatusBadGateway}, transient},
+		{rpc.HTTPError{StatusCode: http.StatusUnauthorized}, permanent},
+		{&revertError{code: 3, data: notAllowedRevert}, permanent},
+		{&jsonRPCError{code: limitExceededCode, message: "limit exceeded"}, rateLimited},
+		{&jsonRPCError{code: -32000, message: "rate limit reached, retry in 1s"}, rateLimited},
+		{&jsonRPCError{code: -32000, message: "header not found"}, permanent},
+		{ethereum.NotFound, permanent},
+		{context.Canceled, permanent},
+		{context.DeadlineExceeded, transient},
+		{fmt.Errorf("read: %w", errors.New("connection reset by peer")), transient},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, classify(tc.err), tc.err.Error())
+	}
+}
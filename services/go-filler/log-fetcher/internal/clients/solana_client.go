@@ -0,0 +1,58 @@
+package clients
+
+import (
+	"context"
+
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/chains"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/ids"
+)
+
+// SolanaClient calls the JSON-RPC API of a Solana node through RPC.
+type SolanaClient struct {
+	rpc *RPC
+}
+
+// SolanaAccount is an account as returned by getAccountInfo.
+type SolanaAccount struct {
+	Lamports   uint64 `json:"lamports"`
+	Owner      string `json:"owner"`
+	Executable bool   `json:"executable"`
+}
+
+func GetSolanaClient(cfg *chains.ChainConfig) (*SolanaClient, error) {
+	r, err := Dial(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SolanaClient{rpc: r}, nil
+}
+
+// GenesisHash returns the base58 genesis hash of the node's cluster.
+func (c *SolanaClient) GenesisHash(ctx context.Context) (string, error) {
+	var hash string
+	err := c.rpc.CallContext(ctx, &hash, "getGenesisHash")
+	return hash, err
+}
+
+// Balance returns the lamports held by an account.
+func (c *SolanaClient) Balance(ctx context.Context, account ids.Pubkey) (uint64, error) {
+	var res struct {
+		Value uint64 `json:"value"`
+	}
+	err := c.rpc.CallContext(ctx, &res, "getBalance", account.String())
+	return res.Value, err
+}
+
+// AccountInfo returns an account, or nil if it doesn't exist.
+func (c *SolanaClient) AccountInfo(ctx context.Context, account ids.Pubkey) (*SolanaAccount, error) {
+	var res struct {
+		Value *SolanaAccount `json:"value"`
+	}
+	err := c.rpc.CallContext(ctx, &res, "getAccountInfo", account.String(), map[string]string{"encoding": "base64"})
+	return res.Value, err
+}
+
+func (c *SolanaClient) Close() {
+	c.rpc.Close()
+}
@@ -0,0 +1,71 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/chains"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/ids"
+	"github.com/stretchr/testify/assert"
+)
+
+var programId, _ = ids.ParsePubkey("2nfLnXeeWyAUBsCT8uskj8nvkk46FiwaaVvDx29zQcue")
+
+// solanaNode answers JSON-RPC requests with the result for their method.
+func solanaNode(t *testing.T, results map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Id     json.RawMessage   `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if req.Method != "getGenesisHash" {
+			assert.JSONEq(t, `"`+programId.String()+`"`, string(req.Params[0]))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(req.Id) + `,"result":` + results[req.Method] + `}`))
+	}))
+}
+
+func TestSolanaClient(t *testing.T) {
+	server := solanaNode(t, map[string]string{
+		"getGenesisHash": `"EtWTRABZaYq6iMfeYKouRu166VU2xqa1wcaWoxPkrZBG"`,
+		"getBalance":     `{"context":{"slot":1},"value":1500000}`,
+		"getAccountInfo": `{"context":{"slot":1},"value":{"lamports":1141440,"owner":"BPFLoaderUpgradeab1e11111111111111111111111","executable":true,"data":["","base64"]}}`,
+	})
+	defer server.Close()
+
+	client, err := GetSolanaClient(&chains.ChainConfig{RpcUrl: server.URL})
+	assert.NoError(t, err)
+	defer client.Close()
+
+	hash, err := client.GenesisHash(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "EtWTRABZaYq6iMfeYKouRu166VU2xqa1wcaWoxPkrZBG", hash)
+
+	balance, err := client.Balance(context.Background(), programId)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1500000), balance)
+
+	account, err := client.AccountInfo(context.Background(), programId)
+	assert.NoError(t, err)
+	assert.Equal(t, &SolanaAccount{Lamports: 1141440, Owner: "BPFLoaderUpgradeab1e11111111111111111111111", Executable: true}, account)
+}
+
+func TestSolanaClientMissingAccount(t *testing.T) {
+	server := solanaNode(t, map[string]string{"getAccountInfo": `{"context":{"slot":1},"value":null}`})
+	defer server.Close()
+
+	client, err := GetSolanaClient(&chains.ChainConfig{RpcUrl: server.URL})
+	assert.NoError(t, err)
+	defer client.Close()
+
+	account, err := client.AccountInfo(context.Background(), programId)
+	assert.NoError(t, err)
+	assert.Nil(t, account)
+}
@@ -0,0 +1,353 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/chains"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/clients"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/ids"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/provers"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ChainClient is the part of a node's API used to check a chain's config.
+type ChainClient interface {
+	ChainID(ctx context.Context) (*big.Int, error)
+	CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error)
+	Close()
+}
+
+// SolanaClient is the part of a Solana node's API used to check a Solana
+// chain's config.
+type SolanaClient interface {
+	GenesisHash(ctx context.Context) (string, error)
+	AccountInfo(ctx context.Context, account ids.Pubkey) (*clients.SolanaAccount, error)
+	Close()
+}
+
+// Dialers connect to the nodes of each kind of chain.
+type Dialers struct {
+	EVM    func(*chains.ChainConfig) (ChainClient, error)
+	Solana func(*chains.ChainConfig) (SolanaClient, error)
+}
+
+var knownProvers = map[provers.Prover]bool{
+	provers.ArbitrumProver: true,
+	provers.OPStackProver:  true,
+	provers.HashiProver:    true,
+}
+
+// Validate checks the config for mistakes that would otherwise only show up as
+// rejected requests. Every chain is keyed by its chain-id, prover contracts and
+// target provers name known provers, every target prover can be reached through
+// another chain's prover contracts, routes point to configured chains, and the
+// addresses needed as a source or destination are set. All problems are
+// returned together.
+func Validate(cfg *chains.NetworksConfig) error {
+	var errs []error
+	fail := func(key, format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("networks.%s.%s", key, fmt.Sprintf(format, args...)))
+	}
+
+	for _, key := range sortedKeys(cfg.Networks) {
+		chain := cfg.Networks[key]
+
+		if chain.ChainId == nil {
+			fail(key, "chain-id: not set")
+		} else if chain.ChainId.String() != key {
+			fail(key, "chain-id: %s doesn't match its key", chain.ChainId)
+		}
+
+		for _, name := range sortedNames(chain.ProverContracts) {
+			if !knownProvers[provers.Prover(name)] {
+				fail(key, "prover-contracts.%s: unknown prover", name)
+			}
+			if chain.ProverContracts[name] == (common.Address{}) {
+				fail(key, "prover-contracts.%s: zero address", name)
+			}
+		}
+
+		if chain.RPC.Timeout < 0 || chain.RPC.RetryBackoff < 0 || chain.RPC.BreakerCooldown < 0 {
+			fail(key, "rpc: durations can't be negative")
+		}
+		if chain.RPC.MaxAttempts < 0 || chain.RPC.BreakerThreshold < 0 {
+			fail(key, "rpc: max-attempts and breaker-threshold can't be negative")
+		}
+
+		switch chain.ChainKind() {
+		case chains.KindEVM:
+		case chains.KindSolana:
+			errs = append(errs, validateSolana(key, &chain)...)
+			continue
+		default:
+			fail(key, "kind: unknown chain kind %s", chain.Kind)
+		}
+
+		if chain.TargetProver != "" && chain.TargetProver != provers.NilProver {
+			if !knownProvers[chain.TargetProver] {
+				fail(key, "target-prover: unknown prover %s", chain.TargetProver)
+			} else if !hasProverContract(cfg.Networks, key, chain.TargetProver) {
+				fail(key, "target-prover: no other chain has a prover contract for %s", chain.TargetProver)
+			}
+
+			if chain.Contracts == nil || chain.Contracts.Inbox == (common.Address{}) {
+				fail(key, "contracts.inbox: required for a destination chain")
+			}
+			if chain.L2Oracle == (common.Address{}) {
+				fail(key, "l2-oracle: required for a destination chain")
+			}
+		}
+
+		if len(chain.Routes) > 0 && (chain.Contracts == nil || chain.Contracts.Outbox == (common.Address{})) {
+			fail(key, "contracts.outbox: required for a source chain")
+		}
+		for dst := range chain.Routes {
+			if _, ok := cfg.Networks[dst]; !ok {
+				fail(key, "routes.%s: unknown chain", dst)
+			}
+		}
+
+		switch chain.Listener.Transport {
+		case "", chains.TransportAuto, chains.TransportWebSocket, chains.TransportPolling:
+		default:
+			fail(key, "listener.transport: unknown transport %s", chain.Listener.Transport)
+		}
+
+		for i, token := range chain.RewardTokens {
+			if token.Address == (common.Address{}) {
+				fail(key, "reward-tokens[%d]: zero address", i)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateSolana checks a Solana chain. Solana chains are only destinations,
+// proven through Hashi, and their calls are Solana instructions, which EVM
+// call rules can't match.
+func validateSolana(key string, chain *chains.ChainConfig) []error {
+	var errs []error
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("networks.%s.%s", key, fmt.Sprintf(format, args...)))
+	}
+
+	if chain.Solana == nil || chain.Solana.ProgramId.IsZero() {
+		fail("solana.program-id: required for a Solana chain")
+	}
+	if chain.Solana == nil || chain.Solana.GenesisHash == "" {
+		fail("solana.genesis-hash: required for a Solana chain")
+	} else if _, err := ids.ParseChainID(chain.ID().String()); err != nil {
+		fail("solana.genesis-hash: %v", err)
+	}
+	if chain.Solana == nil || chain.Solana.Fulfiller.IsZero() {
+		fail("solana.fulfiller: required for a Solana chain")
+	}
+
+	if chain.TargetProver != "" {
+		fail("target-prover: requests to Solana are proven through Hashi")
+	}
+	if chain.Contracts != nil || len(chain.ProverContracts) > 0 || len(chain.Routes) > 0 {
+		fail("routes: a Solana chain can't be a source chain")
+	}
+	if len(chain.CallPolicy.Allow) > 0 || len(chain.CallPolicy.Deny) > 0 {
+		fail("call-policy: allow and deny rules only apply to EVM chains")
+	}
+
+	return errs
+}
+
+// RequireRpcUrls checks that the source chains and every chain their requests
+// can be filled on are configured with an RPC. Other chains are never dialed
+// and may leave rpc-url unset.
+func RequireRpcUrls(cfg *chains.NetworksConfig, sources []string) error {
+	var errs []error
+	required := make(map[string]string)
+	for _, src := range sources {
+		chain, ok := cfg.Networks[src]
+		if !ok {
+			errs = append(errs, fmt.Errorf("networks.%s: source chain not configured", src))
+			continue
+		}
+		if chain.ChainKind() != chains.KindEVM {
+			errs = append(errs, fmt.Errorf("networks.%s: a %s chain can't be a source chain", src, chain.ChainKind()))
+			continue
+		}
+		required[src] = "source"
+		for _, dst := range cfg.Networks.Destinations(src) {
+			if _, ok := required[dst]; !ok {
+				required[dst] = "destination"
+			}
+		}
+	}
+
+	for _, key := range sortedKeys(cfg.Networks) {
+		if role, ok := required[key]; ok && cfg.Networks[key].RpcUrl == "" {
+			errs = append(errs, fmt.Errorf("networks.%s.rpc-url: required for a %s chain", key, role))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func hasProverContract(networks chains.Networks, key string, prover provers.Prover) bool {
+	for other, chain := range networks {
+		if other == key {
+			continue
+		}
+		if _, ok := chain.ProverContracts[string(prover)]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Verify checks the config against the chains themselves: each RPC must serve
+// the configured chain id, and each contract configured on a chain must have
+// code there. Chains without an RPC are skipped. The L2 oracle lives on L1 and
+// is checked where it is listed under an L1 chain's contracts. Solana chains
+// must be on the configured cluster and their program must be deployed.
+func Verify(ctx context.Context, cfg *chains.NetworksConfig, dial Dialers) error {
+	var errs []error
+	for _, key := range sortedKeys(cfg.Networks) {
+		chain := cfg.Networks[key]
+
+		var chainErrs []error
+		if chain.ChainKind() == chains.KindSolana {
+			chainErrs = verifySolana(ctx, &chain, dial.Solana)
+		} else {
+			chainErrs = verifyChain(ctx, &chain, dial.EVM)
+		}
+		for _, err := range chainErrs {
+			errs = append(errs, fmt.Errorf("networks.%s.%v", key, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func verifyChain(ctx context.Context, chain *chains.ChainConfig, dial func(*chains.ChainConfig) (ChainClient, error)) []error {
+	if chain.RpcUrl == "" {
+		return nil
+	}
+
+	client, err := dial(chain)
+	if err != nil {
+		return []error{fmt.Errorf("rpc-url: failed to connect to %s: %s", clients.RedactURL(chain.RpcUrl), clients.RedactError(err, chain.RpcUrl))}
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	chainId, err := client.ChainID(ctx)
+	if err != nil {
+		return []error{fmt.Errorf("rpc-url: failed to get chain id: %s", clients.RedactError(err, chain.RpcUrl))}
+	}
+	if chain.ChainId != nil && chainId.Cmp(chain.ChainId) != 0 {
+		return []error{fmt.Errorf("rpc-url: serves chain %s, expected %s", chainId, chain.ChainId)}
+	}
+
+	var errs []error
+	for _, c := range contracts(chain) {
+		code, err := client.CodeAt(ctx, c.address, nil)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: failed to get code: %s", c.name, clients.RedactError(err, chain.RpcUrl)))
+		} else if len(code) == 0 {
+			errs = append(errs, fmt.Errorf("%s: no contract at %s", c.name, c.address))
+		}
+	}
+
+	return errs
+}
+
+func verifySolana(ctx context.Context, chain *chains.ChainConfig, dial func(*chains.ChainConfig) (SolanaClient, error)) []error {
+	if chain.RpcUrl == "" || chain.Solana == nil {
+		return nil
+	}
+
+	client, err := dial(chain)
+	if err != nil {
+		return []error{fmt.Errorf("rpc-url: failed to connect to %s: %s", clients.RedactURL(chain.RpcUrl), clients.RedactError(err, chain.RpcUrl))}
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	hash, err := client.GenesisHash(ctx)
+	if err != nil {
+		return []error{fmt.Errorf("rpc-url: failed to get genesis hash: %s", clients.RedactError(err, chain.RpcUrl))}
+	}
+	if hash != chain.Solana.GenesisHash {
+		return []error{fmt.Errorf("rpc-url: serves cluster %s, expected %s", hash, chain.Solana.GenesisHash)}
+	}
+
+	program, err := client.AccountInfo(ctx, chain.Solana.ProgramId)
+	if err != nil {
+		return []error{fmt.Errorf("solana.program-id: failed to get account: %s", clients.RedactError(err, chain.RpcUrl))}
+	}
+	if program == nil || !program.Executable {
+		return []error{fmt.Errorf("solana.program-id: no program at %s", chain.Solana.ProgramId)}
+	}
+
+	return nil
+}
+
+type contract struct {
+	name    string
+	address common.Address
+}
+
+// contracts lists the non-zero contract addresses configured on a chain.
+func contracts(chain *chains.ChainConfig) []contract {
+	var list []contract
+	add := func(name string, addr common.Address) {
+		if addr != (common.Address{}) {
+			list = append(list, contract{name, addr})
+		}
+	}
+
+	if c := chain.Contracts; c != nil {
+		add("contracts.anchor-state-registry", c.AnchorStateRegistry)
+		add("contracts.arb-rollup", c.ArbRollup)
+		add("contracts.l2-message-passer", c.L2MessagePasser)
+		add("contracts.inbox", c.Inbox)
+		add("contracts.outbox", c.Outbox)
+		add("contracts.gas-price-oracle", c.GasPriceOracle)
+	}
+
+	for _, name := range sortedNames(chain.ProverContracts) {
+		add("prover-contracts."+name, chain.ProverContracts[name])
+	}
+
+	add("shoyu-bashi", chain.ShoyuBashi)
+	for i, token := range chain.RewardTokens {
+		add(fmt.Sprintf("reward-tokens[%d]", i), token.Address)
+	}
+
+	return list
+}
+
+func sortedKeys(networks chains.Networks) []string {
+	keys := make([]string, 0, len(networks))
+	for key := range networks {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedNames(contracts map[string]common.Address) []string {
+	names := make([]string, 0, len(contracts))
+	for name := range contracts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
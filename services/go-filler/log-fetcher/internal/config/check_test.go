@@ -0,0 +1,274 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/chains"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/clients"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/ids"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/provers"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type ChainClientMock struct {
+	mock.Mock
+}
+
+func (c *ChainClientMock) ChainID(ctx context.Context) (*big.Int, error) {
+	args := c.Called(ctx)
+	return args.Get(0).(*big.Int), args.Error(1)
+}
+
+func (c *ChainClientMock) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	args := c.Called(ctx, account, blockNumber)
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (c *ChainClientMock) Close() {}
+
+type SolanaClientMock struct {
+	mock.Mock
+}
+
+func (c *SolanaClientMock) GenesisHash(ctx context.Context) (string, error) {
+	args := c.Called(ctx)
+	return args.String(0), args.Error(1)
+}
+
+func (c *SolanaClientMock) AccountInfo(ctx context.Context, account ids.Pubkey) (*clients.SolanaAccount, error) {
+	args := c.Called(ctx, account)
+	return args.Get(0).(*clients.SolanaAccount), args.Error(1)
+}
+
+func (c *SolanaClientMock) Close() {}
+
+var (
+	inbox  = common.HexToAddress("0xB482b292878FDe64691d028A2237B34e91c7c7ea")
+	outbox = common.HexToAddress("0xD7a5A114A07cC4B5ebd9C5e1cD1136a99fFA3d68")
+	prover = common.HexToAddress("0x562879614C9Db8Da9379be1D5B52BAEcDD456d78")
+)
+
+func validConfig() *chains.NetworksConfig {
+	return &chains.NetworksConfig{Networks: chains.Networks{
+		"84532": {
+			ChainId:      big.NewInt(84532),
+			RpcUrl:       "https://base-sepolia.example.com",
+			L2Oracle:     common.HexToAddress("0x4C8BA32A5DAC2A720bb35CeDB51D6B067D104205"),
+			Contracts:    &chains.Contracts{Inbox: inbox},
+			TargetProver: provers.OPStackProver,
+		},
+		"421614": {
+			ChainId:         big.NewInt(421614),
+			RpcUrl:          "https://arb-sepolia.example.com",
+			ProverContracts: map[string]common.Address{"OPStack": prover},
+			Contracts:       &chains.Contracts{Outbox: outbox},
+			TargetProver:    provers.NilProver,
+			Routes:          map[string]chains.RouteConfig{"84532": {}},
+		},
+	}}
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Validate(validConfig()))
+}
+
+func TestValidateDefaultConfig(t *testing.T) {
+	cfg, err := Load("../../config/networks.yaml")
+	assert.NoError(t, err)
+
+	assert.NoError(t, Validate(cfg))
+}
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+	cfg := validConfig()
+
+	base := cfg.Networks["84532"]
+	base.ChainId = big.NewInt(8453)
+	base.TargetProver = provers.ArbitrumProver
+	base.Contracts = nil
+	cfg.Networks["84532"] = base
+
+	arb := cfg.Networks["421614"]
+	arb.ProverContracts = map[string]common.Address{"OPStak": prover, "Hashi": {}}
+	arb.Contracts = &chains.Contracts{}
+	arb.Routes["10"] = chains.RouteConfig{}
+	arb.Listener.Transport = "grpc"
+	arb.RPC = chains.RPCConfig{Timeout: -time.Second, MaxAttempts: -1}
+	cfg.Networks["421614"] = arb
+
+	err := Validate(cfg)
+	for _, msg := range []string{
+		"networks.84532.chain-id: 8453 doesn't match its key",
+		"networks.84532.target-prover: no other chain has a prover contract for Arbitrum",
+		"networks.84532.contracts.inbox: required for a destination chain",
+		"networks.421614.prover-contracts.OPStak: unknown prover",
+		"networks.421614.prover-contracts.Hashi: zero address",
+		"networks.421614.contracts.outbox: required for a source chain",
+		"networks.421614.routes.10: unknown chain",
+		"networks.421614.listener.transport: unknown transport grpc",
+		"networks.421614.rpc: durations can't be negative",
+		"networks.421614.rpc: max-attempts and breaker-threshold can't be negative",
+	} {
+		assert.ErrorContains(t, err, msg)
+	}
+}
+
+const devnetGenesisHash = "EtWTRABZaYq6iMfeYKouRu166VU2xqa1wcaWoxPkrZBG"
+
+var programId, _ = ids.ParsePubkey("2nfLnXeeWyAUBsCT8uskj8nvkk46FiwaaVvDx29zQcue")
+
+func solanaChain() chains.ChainConfig {
+	fulfiller, _ := ids.ParsePubkey("4rPLqoMbtPAMdYeytQagQyt5ucVxRJpx7BjL2jW49UsQ")
+	return chains.ChainConfig{
+		Kind:    chains.KindSolana,
+		ChainId: big.NewInt(103),
+		RpcUrl:  "https://api.devnet.solana.com",
+		Solana:  &chains.SolanaConfig{ProgramId: programId, GenesisHash: devnetGenesisHash, Fulfiller: fulfiller},
+	}
+}
+
+func TestValidateSolana(t *testing.T) {
+	cfg := validConfig()
+	cfg.Networks["103"] = solanaChain()
+	assert.NoError(t, Validate(cfg))
+
+	cfg.Networks["103"] = chains.ChainConfig{
+		Kind:         chains.KindSolana,
+		ChainId:      big.NewInt(103),
+		TargetProver: provers.OPStackProver,
+		Routes:       map[string]chains.RouteConfig{"84532": {}},
+		CallPolicy:   chains.CallPolicy{Deny: []chains.CallRule{{}}},
+	}
+	cfg.Networks["1"] = chains.ChainConfig{Kind: "move", ChainId: big.NewInt(1)}
+
+	err := Validate(cfg)
+	for _, msg := range []string{
+		"networks.103.solana.program-id: required for a Solana chain",
+		"networks.103.solana.genesis-hash: required for a Solana chain",
+		"networks.103.solana.fulfiller: required for a Solana chain",
+		"networks.103.target-prover: requests to Solana are proven through Hashi",
+		"networks.103.routes: a Solana chain can't be a source chain",
+		"networks.103.call-policy: allow and deny rules only apply to EVM chains",
+		"networks.1.kind: unknown chain kind move",
+	} {
+		assert.ErrorContains(t, err, msg)
+	}
+}
+
+func TestVerifySolana(t *testing.T) {
+	cfg := &chains.NetworksConfig{Networks: chains.Networks{"103": solanaChain()}}
+
+	client := new(SolanaClientMock)
+	client.On("GenesisHash", mock.Anything).Return(devnetGenesisHash, nil)
+	client.On("AccountInfo", mock.Anything, programId).Return(&clients.SolanaAccount{Executable: true}, nil)
+	dial := Dialers{Solana: func(*chains.ChainConfig) (SolanaClient, error) { return client, nil }}
+
+	assert.NoError(t, Verify(context.Background(), cfg, dial))
+
+	missing := new(SolanaClientMock)
+	missing.On("GenesisHash", mock.Anything).Return(devnetGenesisHash, nil)
+	missing.On("AccountInfo", mock.Anything, programId).Return((*clients.SolanaAccount)(nil), nil)
+	dial = Dialers{Solana: func(*chains.ChainConfig) (SolanaClient, error) { return missing, nil }}
+
+	assert.ErrorContains(t, Verify(context.Background(), cfg, dial), "networks.103.solana.program-id: no program at "+programId.String())
+
+	mainnet := new(SolanaClientMock)
+	mainnet.On("GenesisHash", mock.Anything).Return("5eykt4UsFv8P8NJdTREpY1vzqKqZKvdpKuc147dw2N9d", nil)
+	dial = Dialers{Solana: func(*chains.ChainConfig) (SolanaClient, error) { return mainnet, nil }}
+
+	assert.ErrorContains(t, Verify(context.Background(), cfg, dial), "networks.103.rpc-url: serves cluster 5eykt4UsFv8P8NJdTREpY1vzqKqZKvdpKuc147dw2N9d, expected "+devnetGenesisHash)
+}
+
+func TestVerify(t *testing.T) {
+	base, arb := new(ChainClientMock), new(ChainClientMock)
+	base.On("ChainID", mock.Anything).Return(big.NewInt(84532), nil)
+	base.On("CodeAt", mock.Anything, inbox, (*big.Int)(nil)).Return([]byte{0x60}, nil)
+	arb.On("ChainID", mock.Anything).Return(big.NewInt(421614), nil)
+	arb.On("CodeAt", mock.Anything, outbox, (*big.Int)(nil)).Return([]byte{0x60}, nil)
+	arb.On("CodeAt", mock.Anything, prover, (*big.Int)(nil)).Return([]byte{0x60}, nil)
+
+	err := Verify(context.Background(), validConfig(), dialer(map[string]ChainClient{"84532": base, "421614": arb}))
+
+	assert.NoError(t, err)
+	base.AssertExpectations(t)
+	arb.AssertExpectations(t)
+}
+
+func TestVerifyReportsWrongChainAndMissingCode(t *testing.T) {
+	base, arb := new(ChainClientMock), new(ChainClientMock)
+	base.On("ChainID", mock.Anything).Return(big.NewInt(8453), nil)
+	arb.On("ChainID", mock.Anything).Return(big.NewInt(421614), nil)
+	arb.On("CodeAt", mock.Anything, outbox, (*big.Int)(nil)).Return([]byte{}, nil)
+	arb.On("CodeAt", mock.Anything, prover, (*big.Int)(nil)).Return([]byte(nil), errors.New("timeout"))
+
+	err := Verify(context.Background(), validConfig(), dialer(map[string]ChainClient{"84532": base, "421614": arb}))
+
+	assert.ErrorContains(t, err, "networks.84532.rpc-url: serves chain 8453, expected 84532")
+	assert.ErrorContains(t, err, "networks.421614.contracts.outbox: no contract at "+outbox.Hex())
+	assert.ErrorContains(t, err, "networks.421614.prover-contracts.OPStack: failed to get code: timeout")
+	base.AssertNotCalled(t, "CodeAt", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestVerifyRedactsUnreachableRpc(t *testing.T) {
+	cfg := validConfig()
+	arb := cfg.Networks["421614"]
+	arb.RpcUrl = "wss://arb-sepolia.example.com/v2/secret-key"
+	cfg.Networks["421614"] = arb
+	delete(cfg.Networks, "84532")
+
+	err := Verify(context.Background(), cfg, Dialers{EVM: func(*chains.ChainConfig) (ChainClient, error) {
+		return nil, errors.New(`dial "wss://arb-sepolia.example.com/v2/secret-key": connection refused`)
+	}})
+
+	assert.ErrorContains(t, err, "networks.421614.rpc-url: failed to connect to wss://arb-sepolia.example.com/REDACTED")
+	assert.NotContains(t, err.Error(), "secret-key")
+}
+
+func TestVerifySkipsChainsWithoutRpc(t *testing.T) {
+	cfg := validConfig()
+	delete(cfg.Networks, "84532")
+	arb := cfg.Networks["421614"]
+	arb.RpcUrl = ""
+	cfg.Networks["421614"] = arb
+
+	assert.NoError(t, Verify(context.Background(), cfg, Dialers{EVM: func(*chains.ChainConfig) (ChainClient, error) {
+		t.Fatal("dialed a chain without an RPC")
+		return nil, nil
+	}}))
+}
+
+func TestRequireRpcUrls(t *testing.T) {
+	cfg := validConfig()
+	cfg.Networks["11155111"] = chains.ChainConfig{ChainId: big.NewInt(11155111), TargetProver: provers.NilProver}
+
+	assert.NoError(t, RequireRpcUrls(cfg, []string{"421614"}))
+
+	base := cfg.Networks["84532"]
+	base.RpcUrl = ""
+	cfg.Networks["84532"] = base
+
+	err := RequireRpcUrls(cfg, []string{"421614", "10"})
+	assert.ErrorContains(t, err, "networks.10: source chain not configured")
+	assert.ErrorContains(t, err, "networks.84532.rpc-url: required for a destination chain")
+	assert.NotContains(t, err.Error(), "11155111")
+
+	solana := solanaChain()
+	solana.RpcUrl = ""
+	cfg.Networks["103"] = solana
+
+	err = RequireRpcUrls(cfg, []string{"421614", "103"})
+	assert.ErrorContains(t, err, "networks.103: a solana chain can't be a source chain")
+	assert.ErrorContains(t, err, "networks.103.rpc-url: required for a destination chain")
+}
+
+func dialer(clients map[string]ChainClient) Dialers {
+	return Dialers{EVM: func(cfg *chains.ChainConfig) (ChainClient, error) {
+		return clients[cfg.ChainId.String()], nil
+	}}
+}
@@ -0,0 +1,159 @@
+// Package config loads the networks config from layered files and
+// environment overrides.
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/chains"
+	"gopkg.in/yaml.v2"
+)
+
+const DefaultPath = "log-fetcher/config/networks.yaml"
+
+// Profiles maps each network profile to its base config file. A profile
+// bundles a chain set with its contracts and prover settings, e.g. the devnet
+// profile mocks L1 finality so proofs are available right away.
+var Profiles = map[string]string{
+	"devnet":  "log-fetcher/config/devnet.yaml",
+	"testnet": DefaultPath,
+	"mainnet": "log-fetcher/config/mainnet.yaml",
+}
+
+// Paths returns the config files to load for a profile: the profile's base
+// file followed by the overlays. The profile files are relative to the
+// services/go-filler directory, so with no profile only the overlays are
+// loaded, wherever they are.
+func Paths(profile string, overlays []string) ([]string, error) {
+	if profile == "" {
+		if len(overlays) == 0 {
+			return nil, fmt.Errorf("no network profile or config files given")
+		}
+		return overlays, nil
+	}
+
+	base, ok := Profiles[profile]
+	if !ok {
+		return nil, fmt.Errorf("unknown network profile %q", profile)
+	}
+
+	return append([]string{base}, overlays...), nil
+}
+
+// Load reads the config files in order, typically a base file, then one for
+// the environment, then local overrides. Environment variables are expanded in
+// each file. Values set in a later file replace those of earlier ones, even
+// with false, 0 or "", while maps such as networks are merged key by key.
+// Overrides from the environment, see ApplyEnv, are applied last.
+func Load(paths ...string) (*chains.NetworksConfig, error) {
+	if len(paths) == 0 {
+		paths = []string{DefaultPath}
+	}
+
+	var cfg chains.NetworksConfig
+	for _, path := range paths {
+		layer, set, err := read(path)
+		if err != nil {
+			return nil, err
+		}
+		merge(reflect.ValueOf(&cfg).Elem(), reflect.ValueOf(layer), set)
+	}
+
+	if err := ApplyEnv(&cfg, os.Environ()); err != nil {
+		return nil, err
+	}
+
+	return &cfg, nil
+}
+
+// read parses the config file at path. It also returns the file as plain YAML,
+// which tells the fields the file sets apart from those it leaves out.
+func read(path string) (chains.NetworksConfig, map[interface{}]interface{}, error) {
+	var cfg chains.NetworksConfig
+	var set map[interface{}]interface{}
+
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, nil, fmt.Errorf("failed to read config file: %v", err)
+	}
+
+	expanded := []byte(os.ExpandEnv(string(file)))
+	if err := yaml.Unmarshal(expanded, &cfg); err != nil {
+		return cfg, nil, fmt.Errorf("failed to parse config file %s: %v", path, err)
+	}
+	if err := yaml.Unmarshal(expanded, &set); err != nil {
+		return cfg, nil, fmt.Errorf("failed to parse config file %s: %v", path, err)
+	}
+
+	return cfg, set, nil
+}
+
+// merge overlays src onto dst, where set is src as plain YAML. Config structs
+// and maps are merged field by field and key by key, anything else is replaced
+// when set in src, even to a zero value such as false, 0 or "".
+func merge(dst, src reflect.Value, set interface{}) {
+	switch {
+	case src.Kind() == reflect.Ptr && isConfigStruct(src.Type().Elem()):
+		if src.IsNil() {
+			return
+		}
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		merge(dst.Elem(), src.Elem(), set)
+	case isConfigStruct(src.Type()):
+		fields, _ := set.(map[interface{}]interface{})
+		for i := 0; i < src.NumField(); i++ {
+			name, _, _ := strings.Cut(src.Type().Field(i).Tag.Get("yaml"), ",")
+			if value, ok := fields[name]; ok && dst.Field(i).CanSet() {
+				merge(dst.Field(i), src.Field(i), value)
+			}
+		}
+	case src.Kind() == reflect.Map:
+		if src.Len() == 0 {
+			return
+		}
+		if dst.IsNil() {
+			dst.Set(reflect.MakeMap(dst.Type()))
+		}
+		entries, _ := set.(map[interface{}]interface{})
+		for _, key := range src.MapKeys() {
+			value := reflect.New(dst.Type().Elem()).Elem()
+			if existing := dst.MapIndex(key); existing.IsValid() {
+				value.Set(existing)
+			}
+			merge(value, src.MapIndex(key), entry(entries, key))
+			dst.SetMapIndex(key, value)
+		}
+	default:
+		dst.Set(src)
+	}
+}
+
+// entry returns the value of key in a plain YAML map. Keys such as chain ids
+// are decoded as numbers there, so they are compared as text.
+func entry(entries map[interface{}]interface{}, key reflect.Value) interface{} {
+	for k, v := range entries {
+		if fmt.Sprint(k) == fmt.Sprint(key.Interface()) {
+			return v
+		}
+	}
+	return nil
+}
+
+// isConfigStruct reports whether t is one of our config sections, as opposed
+// to a value type like big.Int.
+func isConfigStruct(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if _, ok := t.Field(i).Tag.Lookup("yaml"); ok {
+			return true
+		}
+	}
+	return false
+}
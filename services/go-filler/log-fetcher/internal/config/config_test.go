@@ -0,0 +1,269 @@
+package config
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/chains"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/provers"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+const base = `
+networks:
+  84532:
+    chain-id: 84532
+    rpc-url: https://base-sepolia.example.com
+    prover-contracts:
+      OPStack: 0x562879614C9Db8Da9379be1D5B52BAEcDD456d78
+    contracts:
+      inbox: 0xB482b292878FDe64691d028A2237B34e91c7c7ea
+      outbox: 0xD7a5A114A07cC4B5ebd9C5e1cD1136a99fFA3d68
+    target-prover: OPStack
+    exposure:
+      max-outstanding: 100000000000000000000
+  421614:
+    chain-id: 421614
+    rpc-url: https://arb-sepolia.example.com
+`
+
+const override = `
+networks:
+  84532:
+    rpc-url: ${TEST_BASE_RPC}
+    prover-contracts:
+      Arbitrum: 0x49E2cDC9e81825B6C718ae8244fe0D5b062F4874
+    contracts:
+      inbox: 0x1111111111111111111111111111111111111111
+`
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadMergesFilesInOrder(t *testing.T) {
+	t.Setenv("TEST_BASE_RPC", "https://local.example.com")
+
+	cfg, err := Load(writeFile(t, "base.yaml", base), writeFile(t, "local.yaml", override))
+	assert.NoError(t, err)
+
+	baseSepolia := cfg.Networks["84532"]
+	assert.Equal(t, "https://local.example.com", baseSepolia.RpcUrl)
+	assert.Equal(t, provers.OPStackProver, baseSepolia.TargetProver)
+	assert.Equal(t, common.HexToAddress("0x562879614C9Db8Da9379be1D5B52BAEcDD456d78"), baseSepolia.ProverContracts["OPStack"])
+	assert.Equal(t, common.HexToAddress("0x49E2cDC9e81825B6C718ae8244fe0D5b062F4874"), baseSepolia.ProverContracts["Arbitrum"])
+	assert.Equal(t, common.HexToAddress("0x1111111111111111111111111111111111111111"), baseSepolia.Contracts.Inbox)
+	assert.Equal(t, common.HexToAddress("0xD7a5A114A07cC4B5ebd9C5e1cD1136a99fFA3d68"), baseSepolia.Contracts.Outbox)
+	assert.Equal(t, "100000000000000000000", baseSepolia.Exposure.MaxOutstanding.String())
+	assert.Equal(t, "https://arb-sepolia.example.com", cfg.Networks["421614"].RpcUrl)
+}
+
+func TestLoadOverridesWithZeroValues(t *testing.T) {
+	cfg, err := Load(writeFile(t, "base.yaml", base+`    exposes-l1-state: true
+    native-symbol: ETH
+    rpc:
+      max-attempts: 5
+      timeout: 5s
+`), writeFile(t, "local.yaml", `
+networks:
+  421614:
+    exposes-l1-state: false
+    native-symbol: ""
+    rpc:
+      max-attempts: 0
+`))
+	assert.NoError(t, err)
+
+	arbSepolia := cfg.Networks["421614"]
+	assert.False(t, *arbSepolia.ExposesL1State)
+	assert.Equal(t, "", arbSepolia.NativeSymbol)
+	assert.Equal(t, 0, arbSepolia.RPC.MaxAttempts)
+	assert.Equal(t, 5*time.Second, arbSepolia.RPC.Timeout)
+	assert.Equal(t, "https://arb-sepolia.example.com", arbSepolia.RpcUrl)
+	assert.Equal(t, "https://base-sepolia.example.com", cfg.Networks["84532"].RpcUrl)
+}
+
+func TestLoadSolanaChain(t *testing.T) {
+	cfg, err := Load(writeFile(t, "solana.yaml", `
+networks:
+  "103":
+    kind: solana
+    chain-id: 103
+    solana:
+      program-id: 2nfLnXeeWyAUBsCT8uskj8nvkk46FiwaaVvDx29zQcue
+      genesis-hash: EtWTRABZaYq6iMfeYKouRu166VU2xqa1wcaWoxPkrZBG
+      fulfiller: 4rPLqoMbtPAMdYeytQagQyt5ucVxRJpx7BjL2jW49UsQ
+`))
+	assert.NoError(t, err)
+
+	chain := cfg.Networks["103"]
+	assert.Equal(t, chains.KindSolana, chain.ChainKind())
+	assert.Equal(t, "2nfLnXeeWyAUBsCT8uskj8nvkk46FiwaaVvDx29zQcue", chain.Solana.ProgramId.String())
+	assert.Equal(t, "4rPLqoMbtPAMdYeytQagQyt5ucVxRJpx7BjL2jW49UsQ", chain.Solana.Fulfiller.String())
+	assert.Equal(t, "solana:EtWTRABZaYq6iMfeYKouRu166VU2xqa1", chain.ID().String())
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	_, err := Load(filepath.Join(t.TempDir(), "missing.yaml"))
+
+	assert.Error(t, err)
+}
+
+func TestLoadDefaultConfig(t *testing.T) {
+	cfg, err := Load("../../config/networks.yaml")
+
+	assert.NoError(t, err)
+	assert.Contains(t, cfg.Networks, "84532")
+}
+
+func TestApplyEnv(t *testing.T) {
+	cfg := &chains.NetworksConfig{Networks: chains.Networks{
+		"84532": {ChainId: big.NewInt(84532), ProverContracts: map[string]common.Address{"OPStack": {}}},
+	}}
+
+	err := ApplyEnv(cfg, []string{
+		"FILLER_NETWORKS__84532__RPC_URL=https://override.example.com",
+		"FILLER_NETWORKS__84532__PROVER_CONTRACTS__OPSTACK=0x562879614C9Db8Da9379be1D5B52BAEcDD456d78",
+		"FILLER_NETWORKS__84532__CONTRACTS__INBOX=0xB482b292878FDe64691d028A2237B34e91c7c7ea",
+		"FILLER_NETWORKS__84532__EXPOSURE__MAX_REQUEST_VALUE=5000000000000000000",
+		"FILLER_NETWORKS__11155420__CHAIN_ID=11155420",
+		"UNRELATED=1",
+	})
+	assert.NoError(t, err)
+
+	baseSepolia := cfg.Networks["84532"]
+	assert.Equal(t, "https://override.example.com", baseSepolia.RpcUrl)
+	assert.Equal(t, common.HexToAddress("0x562879614C9Db8Da9379be1D5B52BAEcDD456d78"), baseSepolia.ProverContracts["OPStack"])
+	assert.Len(t, baseSepolia.ProverContracts, 1)
+	assert.Equal(t, common.HexToAddress("0xB482b292878FDe64691d028A2237B34e91c7c7ea"), baseSepolia.Contracts.Inbox)
+	assert.Equal(t, big.NewInt(5_000_000_000_000_000_000), baseSepolia.Exposure.MaxRequestValue)
+	assert.Equal(t, big.NewInt(84532), baseSepolia.ChainId)
+	assert.Equal(t, big.NewInt(11155420), cfg.Networks["11155420"].ChainId)
+}
+
+func TestApplyEnvUnknownField(t *testing.T) {
+	err := ApplyEnv(&chains.NetworksConfig{}, []string{"FILLER_NETWORKS__84532__RPC=https://example.com"})
+
+	assert.Error(t, err)
+}
+
+func TestRedacted(t *testing.T) {
+	cfg := &chains.NetworksConfig{Networks: chains.Networks{
+		"84532": {ChainId: big.NewInt(84532), RpcUrl: "https://base-sepolia.g.alchemy.com/v2/secret-key"},
+	}}
+
+	out, err := Redacted(cfg)
+
+	assert.NoError(t, err)
+	assert.False(t, strings.Contains(out, "secret-key"))
+	assert.Contains(t, out, "https://base-sepolia.g.alchemy.com/REDACTED")
+	assert.Equal(t, "https://base-sepolia.g.alchemy.com/v2/secret-key", cfg.Networks["84532"].RpcUrl)
+}
+
+func TestDiff(t *testing.T) {
+	prev := chains.Networks{
+		"84532":    {ChainId: big.NewInt(84532), RpcUrl: "wss://base-sepolia.example.com"},
+		"421614":   {ChainId: big.NewInt(421614), RpcUrl: "wss://arb-sepolia.example.com"},
+		"11155420": {ChainId: big.NewInt(11155420)},
+	}
+	next := chains.Networks{
+		"84532":    {ChainId: big.NewInt(84532), RpcUrl: "wss://base-sepolia.example.com"},
+		"421614":   {ChainId: big.NewInt(421614), RpcUrl: "wss://arb-sepolia.other.com"},
+		"11155111": {ChainId: big.NewInt(11155111)},
+	}
+
+	assert.Equal(t, []string{"11155111", "11155420", "421614"}, Diff(prev, next))
+	assert.Empty(t, Diff(prev, prev))
+}
+
+func TestRedial(t *testing.T) {
+	prev := chains.Networks{
+		"84532":    {ChainId: big.NewInt(84532), RpcUrl: "wss://base-sepolia.example.com"},
+		"421614":   {ChainId: big.NewInt(421614), RpcUrl: "wss://arb-sepolia.example.com"},
+		"11155420": {ChainId: big.NewInt(11155420), RpcUrl: "https://op-sepolia.example.com"},
+		"11155111": {ChainId: big.NewInt(11155111), RpcUrl: "https://sepolia.example.com"},
+	}
+	next := chains.Networks{
+		"84532":    {ChainId: big.NewInt(84532), RpcUrl: "wss://base-sepolia.example.com", NativeSymbol: "ETH"},
+		"421614":   {ChainId: big.NewInt(421614), RpcUrl: "wss://arb-sepolia.other.com"},
+		"11155420": {ChainId: big.NewInt(11155420), RpcUrl: "https://op-sepolia.example.com", RPC: chains.RPCConfig{Timeout: time.Second}},
+		"10":       {ChainId: big.NewInt(10), RpcUrl: "https://optimism.example.com"},
+	}
+
+	assert.Equal(t, []string{"11155111", "11155420", "421614"}, Redial(prev, next, Diff(prev, next)))
+}
+
+func TestPaths(t *testing.T) {
+	paths, err := Paths("devnet", []string{"local.yaml"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"log-fetcher/config/devnet.yaml", "local.yaml"}, paths)
+
+	_, err = Paths("staging", nil)
+	assert.ErrorContains(t, err, `unknown network profile "staging"`)
+
+	paths, err = Paths("", []string{"/etc/filler/networks.yaml"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/etc/filler/networks.yaml"}, paths)
+
+	_, err = Paths("", nil)
+	assert.Error(t, err)
+}
+
+func TestProfiles(t *testing.T) {
+	for profile, path := range Profiles {
+		t.Run(profile, func(t *testing.T) {
+			cfg, err := Load(filepath.Join("../..", strings.TrimPrefix(path, "log-fetcher/")))
+			assert.NoError(t, err)
+			assert.NotEmpty(t, cfg.Networks)
+			for _, chainId := range cfg.SupportedChains {
+				assert.Contains(t, cfg.Networks, chainId)
+			}
+
+			// mainnet has no RRC-7755 deployment to point at yet
+			if profile != "mainnet" {
+				assert.NoError(t, Validate(cfg))
+			}
+		})
+	}
+}
+
+// The route flags match the TS filler's exposesL1State and sharesStateWithL1.
+func TestTestnetProverSelection(t *testing.T) {
+	cfg, err := Load("../../config/networks.yaml")
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		src, dst string
+		expected provers.Prover
+	}{
+		{"421614", "84532", provers.HashiProver},
+		{"84532", "421614", provers.ArbitrumProver},
+		{"84532", "11155420", provers.OPStackProver},
+		{"11155420", "11155111", provers.HashiProver},
+	}
+
+	for _, tc := range testCases {
+		src, dst := cfg.Networks[tc.src], cfg.Networks[tc.dst]
+		assert.Equal(t, tc.expected, src.SelectProver(&dst), tc.src+" to "+tc.dst)
+	}
+}
+
+func TestDevnetProfileMocksProofs(t *testing.T) {
+	cfg, err := Load("../../config/devnet.yaml")
+	assert.NoError(t, err)
+
+	chainA, chainB := cfg.Networks["111111"], cfg.Networks["111112"]
+	assert.True(t, chainA.MocksProofs(&chainB))
+	assert.True(t, chainB.MocksProofs(&chainA))
+	assert.Equal(t, uint64(5), chainA.GetProverLatency(chainA.SelectProver(&chainB)).ClaimSeconds)
+	assert.Equal(t, []string{"111111", "111112"}, cfg.SupportedChains)
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/chains"
+)
+
+// Diff returns the keys, in order, of the chains that were added, removed or
+// changed between two configs.
+func Diff(prev, next chains.Networks) []string {
+	var keys []string
+	for key, chain := range next {
+		if old, ok := prev[key]; !ok || !reflect.DeepEqual(old, chain) {
+			keys = append(keys, key)
+		}
+	}
+	for key := range prev {
+		if _, ok := next[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Redial returns the keys among changed whose clients must be dialed again,
+// those whose node moved or whose rpc settings changed, and those removed.
+func Redial(prev, next chains.Networks, changed []string) []string {
+	var keys []string
+	for _, key := range changed {
+		old, ok := prev[key]
+		if !ok {
+			continue
+		}
+		if chain, ok := next[key]; !ok || chain.RpcUrl != old.RpcUrl || chain.RPC != old.RPC {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/chains"
+	"gopkg.in/yaml.v2"
+)
+
+// EnvPrefix starts every config override in the environment.
+const EnvPrefix = "FILLER_"
+
+// ApplyEnv applies overrides from env, given as KEY=value pairs. An override
+// names a config path with segments separated by a double underscore and
+// dashes written as single underscores, matched case-insensitively. For
+// example FILLER_NETWORKS__84532__RPC_URL sets networks.84532.rpc-url and
+// FILLER_NETWORKS__84532__PROVER_CONTRACTS__OPSTACK sets the chain's OPStack
+// prover. Values are parsed as YAML.
+func ApplyEnv(cfg *chains.NetworksConfig, env []string) error {
+	for _, kv := range env {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok || !strings.HasPrefix(key, EnvPrefix) {
+			continue
+		}
+
+		path := strings.Split(strings.TrimPrefix(key, EnvPrefix), "__")
+		for i, segment := range path {
+			path[i] = strings.ReplaceAll(segment, "_", "-")
+		}
+
+		if err := set(reflect.ValueOf(cfg).Elem(), path, value); err != nil {
+			return fmt.Errorf("invalid override %s: %v", key, err)
+		}
+	}
+
+	return nil
+}
+
+func set(v reflect.Value, path []string, value string) error {
+	if len(path) == 0 {
+		return yaml.Unmarshal([]byte(value), v.Addr().Interface())
+	}
+
+	switch {
+	case v.Kind() == reflect.Ptr && isConfigStruct(v.Type().Elem()):
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return set(v.Elem(), path, value)
+	case isConfigStruct(v.Type()):
+		for i := 0; i < v.NumField(); i++ {
+			name, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("yaml"), ",")
+			if strings.EqualFold(name, path[0]) {
+				return set(v.Field(i), path[1:], value)
+			}
+		}
+		return fmt.Errorf("unknown field %q", path[0])
+	case v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String:
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+		key := reflect.ValueOf(path[0]).Convert(v.Type().Key())
+		for _, existing := range v.MapKeys() {
+			if strings.EqualFold(existing.String(), path[0]) {
+				key = existing
+			}
+		}
+
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if existing := v.MapIndex(key); existing.IsValid() {
+			elem.Set(existing)
+		}
+		if err := set(elem, path[1:], value); err != nil {
+			return err
+		}
+		v.SetMapIndex(key, elem)
+		return nil
+	default:
+		return fmt.Errorf("can't override inside %q", path[0])
+	}
+}
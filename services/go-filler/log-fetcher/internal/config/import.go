@@ -0,0 +1,326 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/provers"
+	"github.com/ethereum/go-ethereum/common"
+	"gopkg.in/yaml.v3"
+)
+
+// ChainNames maps the network names used in contracts/addresses.json to chain
+// ids.
+var ChainNames = map[string]string{
+	"sepolia":         "11155111",
+	"arbitrumSepolia": "421614",
+	"baseSepolia":     "84532",
+	"optimismSepolia": "11155420",
+}
+
+// Deployments holds the imported addresses by chain id and then by config
+// path within the chain, e.g. contracts.inbox or prover-contracts.OPStack.
+type Deployments map[string]map[string]common.Address
+
+// Change is a config value set by an import. Old is empty for new values.
+type Change struct {
+	Path string
+	Old  string
+	New  string
+}
+
+func (c Change) String() string {
+	if c.Old == "" {
+		return fmt.Sprintf("+ %s: %s", c.Path, c.New)
+	}
+	return fmt.Sprintf("~ %s: %s -> %s", c.Path, c.Old, c.New)
+}
+
+// configPath returns where a deployed contract goes in a chain's config. The
+// filler listens to RIP-7755 outboxes, so only RIP-7755 contracts are imported
+// and RRC-7755 ones are skipped. The RIP7755OutboxTo<Prover> outboxes each
+// prove requests to chains with one prover, so they are imported as that
+// prover's contract.
+func configPath(contractName string) (string, bool) {
+	switch contractName {
+	case "RIP7755Inbox":
+		return "contracts.inbox", true
+	case "RIP7755Outbox":
+		return "contracts.outbox", true
+	}
+	if prover, ok := strings.CutPrefix(contractName, "RIP7755OutboxTo"); ok && knownProvers[provers.Prover(prover)] {
+		return "prover-contracts." + prover, true
+	}
+	return "", false
+}
+
+// ReadBroadcasts reads the contracts created by every forge broadcast under
+// dir, i.e. dir/<script>/<chain id>/run-latest.json. Broadcasts are applied
+// oldest first, so the latest deployment of a contract wins.
+func ReadBroadcasts(dir string, into Deployments) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*", "*", "run-latest.json"))
+	if err != nil {
+		return err
+	}
+
+	type broadcast struct {
+		Chain        uint64 `json:"chain"`
+		Timestamp    uint64 `json:"timestamp"`
+		Transactions []struct {
+			TransactionType string `json:"transactionType"`
+			ContractName    string `json:"contractName"`
+			ContractAddress string `json:"contractAddress"`
+		} `json:"transactions"`
+	}
+
+	var runs []broadcast
+	for _, path := range paths {
+		file, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read broadcast: %v", err)
+		}
+		var run broadcast
+		if err := json.Unmarshal(file, &run); err != nil {
+			return fmt.Errorf("failed to parse broadcast %s: %v", path, err)
+		}
+		runs = append(runs, run)
+	}
+	sort.SliceStable(runs, func(i, j int) bool { return runs[i].Timestamp < runs[j].Timestamp })
+
+	for _, run := range runs {
+		for _, tx := range run.Transactions {
+			path, ok := configPath(tx.ContractName)
+			if !ok || tx.TransactionType != "CREATE" || !common.IsHexAddress(tx.ContractAddress) {
+				continue
+			}
+			into.set(fmt.Sprint(run.Chain), path, common.HexToAddress(tx.ContractAddress))
+		}
+	}
+
+	return nil
+}
+
+// ReadAddresses reads contracts/addresses.json, which lists the current
+// deployments by network name and contract name.
+func ReadAddresses(path string, into Deployments) error {
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read addresses: %v", err)
+	}
+
+	var addresses map[string]map[string]string
+	if err := json.Unmarshal(file, &addresses); err != nil {
+		return fmt.Errorf("failed to parse addresses %s: %v", path, err)
+	}
+
+	for network, contracts := range addresses {
+		chainId, ok := ChainNames[network]
+		if !ok {
+			return fmt.Errorf("unknown network %s in %s", network, path)
+		}
+		for name, addr := range contracts {
+			field, ok := configPath(name)
+			if !ok || !common.IsHexAddress(addr) {
+				continue
+			}
+			into.set(chainId, field, common.HexToAddress(addr))
+		}
+	}
+
+	return nil
+}
+
+func (d Deployments) set(chainId, path string, addr common.Address) {
+	if d[chainId] == nil {
+		d[chainId] = make(map[string]common.Address)
+	}
+	d[chainId][path] = addr
+}
+
+// Import writes the deployments into the networks config at path, adding
+// chains that aren't configured yet. Each change is written as a line edit, so
+// everything else in the file, including comments, quoting and environment
+// variables, is left byte for byte. The changes are returned in order, and
+// nothing is written if write is false.
+func Import(path string, deployments Deployments, write bool) ([]Change, error) {
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %v", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(file, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %v", path, err)
+	}
+
+	type edit struct {
+		keys  []string
+		value string
+	}
+
+	var changes []Change
+	var edits []edit
+	for _, chainId := range sortedDeploymentKeys(deployments) {
+		chain := lookup(root(&doc), "networks", chainId)
+		if lookup(chain, "chain-id") == nil {
+			edits = append(edits, edit{[]string{"networks", chainId, "chain-id"}, chainId})
+		}
+
+		contracts := deployments[chainId]
+		fields := make([]string, 0, len(contracts))
+		for field := range contracts {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+
+		for _, field := range fields {
+			section, key, _ := strings.Cut(field, ".")
+			addr := contracts[field].Hex()
+
+			var old string
+			if value := lookup(chain, section, key); value != nil {
+				old = value.Value
+				if common.IsHexAddress(old) && common.HexToAddress(old).Hex() == addr {
+					continue
+				}
+			}
+
+			edits = append(edits, edit{[]string{"networks", chainId, section, key}, addr})
+			changes = append(changes, Change{Path: "networks." + chainId + "." + field, Old: old, New: addr})
+		}
+	}
+
+	if !write || len(changes) == 0 {
+		return changes, nil
+	}
+
+	for _, e := range edits {
+		if file, err = setLine(file, e.keys, e.value); err != nil {
+			return nil, fmt.Errorf("failed to update config file %s: %v", path, err)
+		}
+	}
+	if err := os.WriteFile(path, file, 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write config file: %v", err)
+	}
+
+	return changes, nil
+}
+
+// setLine sets the scalar at keys to value. An existing value is replaced in
+// place, keeping its quotes and comment. Missing keys are added as block
+// mappings below the last line of their parent, indented like its other keys.
+func setLine(file []byte, keys []string, value string) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(file, &doc); err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(string(file), "\n")
+	node := root(&doc)
+	indent, at := 0, len(lines)
+	if lines[len(lines)-1] == "" {
+		at--
+	}
+
+	for i, key := range keys {
+		if node == nil {
+			return insert(lines, at, indent, keys[i:], value), nil
+		}
+		if node.Kind != yaml.MappingNode || node.Style&yaml.FlowStyle != 0 {
+			return nil, fmt.Errorf("line %d: %s is not a block mapping", node.Line, strings.Join(keys[:i], "."))
+		}
+
+		k, v := pair(node, key)
+		if v == nil {
+			return insert(lines, lastLine(node), node.Content[0].Column-1, keys[i:], value), nil
+		}
+
+		if i == len(keys)-1 {
+			if v.Kind != yaml.ScalarNode {
+				return nil, fmt.Errorf("line %d: %s is not a scalar", v.Line, strings.Join(keys, "."))
+			}
+			line, start := lines[v.Line-1], v.Column-1
+			if v.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle) != 0 {
+				start++
+			}
+			if !strings.HasPrefix(line[start:], v.Value) {
+				return nil, fmt.Errorf("line %d: can't find the value of %s", v.Line, strings.Join(keys, "."))
+			}
+			lines[v.Line-1] = line[:start] + value + line[start+len(v.Value):]
+			return []byte(strings.Join(lines, "\n")), nil
+		}
+
+		// An empty value, as in `prover-contracts:` with nothing below, gets
+		// the rest of the keys as its children.
+		node = v
+		if v.Kind == yaml.ScalarNode && v.Tag == "!!null" && v.Value == "" {
+			node, indent, at = nil, k.Column+1, k.Line
+		}
+	}
+
+	return []byte(strings.Join(lines, "\n")), nil
+}
+
+// insert adds keys as nested block mappings ending in value after the first at
+// lines.
+func insert(lines []string, at, indent int, keys []string, value string) []byte {
+	added := make([]string, len(keys))
+	for i, key := range keys {
+		added[i] = strings.Repeat(" ", indent+2*i) + key + ":"
+	}
+	added[len(added)-1] += " " + value
+
+	out := append(append(append([]string{}, lines[:at]...), added...), lines[at:]...)
+	return []byte(strings.Join(out, "\n"))
+}
+
+// root returns the top level mapping of a document, or nil if it is empty.
+func root(doc *yaml.Node) *yaml.Node {
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil
+	}
+	return doc.Content[0]
+}
+
+// lookup returns the value at keys below a mapping node, or nil.
+func lookup(node *yaml.Node, keys ...string) *yaml.Node {
+	for _, key := range keys {
+		if node == nil || node.Kind != yaml.MappingNode {
+			return nil
+		}
+		_, node = pair(node, key)
+	}
+	return node
+}
+
+// pair returns the key and value nodes of key in a mapping node.
+func pair(node *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i], node.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+// lastLine returns the last line a node spans.
+func lastLine(node *yaml.Node) int {
+	last := node.Line
+	for _, child := range node.Content {
+		last = max(last, lastLine(child))
+	}
+	return last
+}
+
+func sortedDeploymentKeys(deployments Deployments) []string {
+	keys := make([]string, 0, len(deployments))
+	for key := range deployments {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
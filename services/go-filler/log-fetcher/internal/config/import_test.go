@@ -0,0 +1,221 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+const networksFile = `networks:
+  84532: # Base Sepolia
+    chain-id: 84532
+    prover-contracts:
+      OPStack: 0x562879614C9Db8Da9379be1D5B52BAEcDD456d78
+    rpc-url: ${BASE_SEPOLIA_RPC}
+    contracts:
+      inbox: 0xB482b292878FDe64691d028A2237B34e91c7c7ea
+`
+
+const addressesFile = `{
+  "baseSepolia": {
+    "RIP7755Inbox": "0x248c18c76445ab8b042d31d7609fffec800a57ba",
+    "RIP7755OutboxToOPStack": "0x562879614c9db8da9379be1d5b52baecdd456d78",
+    "RRC7755Inbox": "0x5c2c743c41d7bff2cb3c1b82edbbb79e5c225baf",
+    "RRC7755OutboxToHashi": "0xbb82b46c2c557861e044a28a666c00b042b82794"
+  }
+}`
+
+const broadcastFile = `{
+  "chain": %s,
+  "timestamp": %d,
+  "transactions": [
+    {"transactionType": "CREATE", "contractName": "RIP7755OutboxToHashi", "contractAddress": "%s"},
+    {"transactionType": "CREATE", "contractName": "RRC7755OutboxToOPStack", "contractAddress": "0x9d052b05d093a466c5138c765b980aa1e8d65dd8"},
+    {"transactionType": "CREATE", "contractName": "Mock", "contractAddress": "0x8e993853c303288f4fcd138e180e31a3c798e4f9"},
+    {"transactionType": "CALL", "contractName": null, "contractAddress": "0x09f9e99d379a9963fe13814b31b90ba81bf9a74f"}
+  ]
+}`
+
+func writeBroadcast(t *testing.T, dir, script, chainId string, timestamp int, hashi string) {
+	path := filepath.Join(dir, script, chainId, "run-latest.json")
+	assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	assert.NoError(t, os.WriteFile(path, []byte(fmt.Sprintf(broadcastFile, chainId, timestamp, hashi)), 0o644))
+}
+
+func TestReadBroadcastsLatestWins(t *testing.T) {
+	dir := t.TempDir()
+	writeBroadcast(t, dir, "DeployNew.s.sol", "84532", 2, "0xe4401eb53ae90a5335a51fe1828d7becf7a63508")
+	writeBroadcast(t, dir, "DeployOld.s.sol", "84532", 1, "0xbb174bdaf21d8ee40763fd5a859b0164365c64ff")
+	writeBroadcast(t, dir, "DeployOld.s.sol", "421614", 1, "0x09f9e99d379a9963fe13814b31b90ba81bf9a74f")
+
+	deployments := Deployments{}
+	assert.NoError(t, ReadBroadcasts(dir, deployments))
+
+	assert.Equal(t, Deployments{
+		"84532":  {"prover-contracts.Hashi": common.HexToAddress("0xe4401eb53ae90a5335a51fe1828d7becf7a63508")},
+		"421614": {"prover-contracts.Hashi": common.HexToAddress("0x09f9e99d379a9963fe13814b31b90ba81bf9a74f")},
+	}, deployments)
+}
+
+func TestConfigPath(t *testing.T) {
+	testCases := map[string]string{
+		"RIP7755Inbox":            "contracts.inbox",
+		"RIP7755Outbox":           "contracts.outbox",
+		"RIP7755OutboxToOPStack":  "prover-contracts.OPStack",
+		"RIP7755OutboxToArbitrum": "prover-contracts.Arbitrum",
+		"RIP7755OutboxToPlonky":   "",
+		"RRC7755Inbox":            "",
+		"RRC7755OutboxToHashi":    "",
+	}
+
+	for name, expected := range testCases {
+		path, ok := configPath(name)
+		assert.Equal(t, expected, path, name)
+		assert.Equal(t, expected != "", ok, name)
+	}
+}
+
+func TestReadAddresses(t *testing.T) {
+	deployments := Deployments{}
+	assert.NoError(t, ReadAddresses(writeFile(t, "addresses.json", addressesFile), deployments))
+
+	assert.Equal(t, Deployments{"84532": {
+		"contracts.inbox":          common.HexToAddress("0x248c18c76445ab8b042d31d7609fffec800a57ba"),
+		"prover-contracts.OPStack": common.HexToAddress("0x562879614c9db8da9379be1d5b52baecdd456d78"),
+	}}, deployments)
+
+	err := ReadAddresses(writeFile(t, "addresses.json", `{"mainnet": {}}`), deployments)
+	assert.ErrorContains(t, err, "unknown network mainnet")
+}
+
+func TestImport(t *testing.T) {
+	path := writeFile(t, "networks.yaml", networksFile)
+	deployments := Deployments{
+		"84532": {
+			"contracts.inbox":          common.HexToAddress("0x248c18c76445ab8b042d31d7609fffec800a57ba"),
+			"prover-contracts.OPStack": common.HexToAddress("0x562879614c9db8da9379be1d5b52baecdd456d78"),
+			"prover-contracts.Hashi":   common.HexToAddress("0xe4401eb53ae90a5335a51fe1828d7becf7a63508"),
+		},
+		"421614": {
+			"contracts.inbox": common.HexToAddress("0x5c2c743c41d7bff2cb3c1b82edbbb79e5c225baf"),
+		},
+	}
+
+	// without write the changes are only reported
+	changes, err := Import(path, deployments, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"+ networks.421614.contracts.inbox: 0x5c2c743c41d7bFf2Cb3c1B82edbBB79E5C225Baf",
+		"~ networks.84532.contracts.inbox: 0xB482b292878FDe64691d028A2237B34e91c7c7ea -> 0x248c18C76445AB8B042d31d7609fFFEc800a57bA",
+		"+ networks.84532.prover-contracts.Hashi: 0xE4401EB53AE90a5335a51fe1828d7BeCf7a63508",
+	}, changeLines(changes))
+	unchanged, _ := os.ReadFile(path)
+	assert.Equal(t, networksFile, string(unchanged))
+
+	_, err = Import(path, deployments, true)
+	assert.NoError(t, err)
+
+	written, _ := os.ReadFile(path)
+	assert.Contains(t, string(written), "84532: # Base Sepolia")
+	assert.Contains(t, string(written), "rpc-url: ${BASE_SEPOLIA_RPC}")
+
+	cfg, err := Load(path)
+	assert.NoError(t, err)
+	assert.Equal(t, common.HexToAddress("0x248c18c76445ab8b042d31d7609fffec800a57ba"), cfg.Networks["84532"].Contracts.Inbox)
+	assert.Equal(t, common.HexToAddress("0xe4401eb53ae90a5335a51fe1828d7becf7a63508"), cfg.Networks["84532"].ProverContracts["Hashi"])
+	assert.Equal(t, "421614", cfg.Networks["421614"].ChainId.String())
+
+	// importing again changes nothing
+	changes, err = Import(path, deployments, true)
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestImportEditsOnlyChangedLines(t *testing.T) {
+	inbox := common.HexToAddress("0x248c18c76445ab8b042d31d7609fffec800a57ba")
+	hashi := common.HexToAddress("0xe4401eb53ae90a5335a51fe1828d7becf7a63508")
+
+	testCases := []struct {
+		file        string
+		deployments Deployments
+		expected    func(original string) string
+	}{
+		{
+			file: "networks.yaml",
+			deployments: Deployments{
+				"84532": {"contracts.inbox": inbox, "prover-contracts.Hashi": hashi},
+				"999":   {"contracts.inbox": inbox},
+			},
+			expected: func(original string) string {
+				out := strings.Replace(original, "      inbox: 0xB482b292878FDe64691d028A2237B34e91c7c7ea\n", "      inbox: "+inbox.Hex()+"\n", 1)
+				out = strings.Replace(out, "      OPStack: 0x562879614C9Db8Da9379be1D5B52BAEcDD456d78\n", "      OPStack: 0x562879614C9Db8Da9379be1D5B52BAEcDD456d78\n      Hashi: "+hashi.Hex()+"\n", 1)
+				return out + "  999:\n    chain-id: 999\n    contracts:\n      inbox: " + inbox.Hex() + "\n"
+			},
+		},
+		{
+			file: "devnet.yaml",
+			deployments: Deployments{
+				"111111": {"contracts.inbox": inbox},
+				"31337":  {"contracts.inbox": inbox},
+			},
+			expected: func(original string) string {
+				out := strings.Replace(original, "      inbox: 0xe7f1725E7734CE288F8367e1Bb143E90bb3F0512\n", "      inbox: "+inbox.Hex()+"\n", 1)
+				return out + "    contracts:\n      inbox: " + inbox.Hex() + "\n"
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.file, func(t *testing.T) {
+			original, err := os.ReadFile(filepath.Join("../../config", tc.file))
+			assert.NoError(t, err)
+			path := writeFile(t, tc.file, string(original))
+
+			_, err = Import(path, tc.deployments, true)
+			assert.NoError(t, err)
+
+			written, _ := os.ReadFile(path)
+			assert.Equal(t, tc.expected(string(original)), string(written))
+		})
+	}
+}
+
+func TestImportIntoEmptySection(t *testing.T) {
+	path := writeFile(t, "networks.yaml", "networks:\n  84532:\n    prover-contracts:\n    contracts:\n      inbox: '0xB482b292878FDe64691d028A2237B34e91c7c7ea' # old\n")
+
+	_, err := Import(path, Deployments{"84532": {
+		"contracts.inbox":          common.HexToAddress("0x248c18c76445ab8b042d31d7609fffec800a57ba"),
+		"prover-contracts.OPStack": common.HexToAddress("0x562879614c9db8da9379be1d5b52baecdd456d78"),
+	}}, true)
+	assert.NoError(t, err)
+
+	written, _ := os.ReadFile(path)
+	assert.Equal(t, `networks:
+  84532:
+    prover-contracts:
+      OPStack: 0x562879614C9Db8Da9379be1D5B52BAEcDD456d78
+    contracts:
+      inbox: '0x248c18C76445AB8B042d31d7609fFFEc800a57bA' # old
+    chain-id: 84532
+`, string(written))
+}
+
+func TestImportFlowMapping(t *testing.T) {
+	path := writeFile(t, "networks.yaml", "networks:\n  84532: {chain-id: 84532, contracts: {inbox: 0xB482b292878FDe64691d028A2237B34e91c7c7ea}}\n")
+
+	_, err := Import(path, Deployments{"84532": {"contracts.inbox": common.HexToAddress("0x248c18c76445ab8b042d31d7609fffec800a57ba")}}, true)
+	assert.ErrorContains(t, err, "networks.84532 is not a block mapping")
+}
+
+func changeLines(changes []Change) []string {
+	out := make([]string, len(changes))
+	for i, change := range changes {
+		out[i] = change.String()
+	}
+	return out
+}
@@ -0,0 +1,22 @@
+package config
+
+import (
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/chains"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/clients"
+	"gopkg.in/yaml.v2"
+)
+
+// Redacted returns the config as YAML with secrets removed. RPC URLs keep only
+// their scheme and host, since providers put API keys in the path, query or
+// user info.
+func Redacted(cfg *chains.NetworksConfig) (string, error) {
+	out := *cfg
+	out.Networks = make(chains.Networks, len(cfg.Networks))
+	for id, chain := range cfg.Networks {
+		chain.RpcUrl = clients.RedactURL(chain.RpcUrl)
+		out.Networks[id] = chain
+	}
+
+	b, err := yaml.Marshal(out)
+	return string(b), err
+}
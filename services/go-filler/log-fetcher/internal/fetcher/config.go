@@ -0,0 +1,118 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/chains"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/clients"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/config"
+	"github.com/urfave/cli/v2"
+)
+
+// CheckConfig validates the networks config for the supported chains and
+// checks it against the configured chains without starting the fetcher.
+func CheckConfig(ctx *cli.Context) error {
+	cfg, _, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := checkConfig(ctx.Context, cfg, supportedChains(ctx, cfg)); err != nil {
+		return cli.Exit(err, 1)
+	}
+
+	fmt.Println("Config OK")
+	return nil
+}
+
+// ImportConfig updates the contracts and prover contracts in the networks
+// config from the forge broadcasts and addresses.json, and shows what changed.
+func ImportConfig(ctx *cli.Context) error {
+	deployments := config.Deployments{}
+	if dir := ctx.String("broadcast"); dir != "" {
+		if err := config.ReadBroadcasts(dir, deployments); err != nil {
+			return err
+		}
+	}
+	// addresses.json lists the current deployments, so it overrides broadcasts.
+	if path := ctx.String("addresses"); path != "" {
+		if err := config.ReadAddresses(path, deployments); err != nil {
+			return err
+		}
+	}
+
+	path := ctx.String("file")
+	if path == "" {
+		path = config.Profiles[ctx.String("network-profile")]
+	}
+	changes, err := config.Import(path, deployments, ctx.Bool("write"))
+	if err != nil {
+		return err
+	}
+
+	if len(changes) == 0 {
+		fmt.Printf("%s is up to date\n", path)
+		return nil
+	}
+	for _, change := range changes {
+		fmt.Println(change)
+	}
+	if !ctx.Bool("write") {
+		fmt.Printf("Run with --write to update %s\n", path)
+	}
+
+	return nil
+}
+
+// loadConfig loads the network profile's config with the --config overlays,
+// and returns the files it was loaded from. Files given with --config stand on
+// their own unless a profile is set explicitly.
+func loadConfig(ctx *cli.Context) (*chains.NetworksConfig, []string, error) {
+	profile, overlays := ctx.String("network-profile"), ctx.StringSlice("config")
+	if len(overlays) > 0 && !ctx.IsSet("network-profile") {
+		profile = ""
+	}
+
+	paths, err := config.Paths(profile, overlays)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cfg, err := config.Load(paths...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cfg, paths, nil
+}
+
+// supportedChains returns the source chains to listen to, taken from
+// --supported-chains if set and from the config otherwise.
+func supportedChains(ctx *cli.Context, cfg *chains.NetworksConfig) []string {
+	if ctx.IsSet("supported-chains") {
+		return ctx.StringSlice("supported-chains")
+	}
+	return cfg.SupportedChains
+}
+
+func checkConfig(ctx context.Context, cfg *chains.NetworksConfig, sources []string) error {
+	if err := errors.Join(config.Validate(cfg), config.RequireRpcUrls(cfg, sources)); err != nil {
+		return fmt.Errorf("invalid config:\n%v", err)
+	}
+
+	if err := config.Verify(ctx, cfg, config.Dialers{EVM: dial, Solana: dialSolana}); err != nil {
+		return fmt.Errorf("config doesn't match the chains:\n%v", err)
+	}
+
+	return nil
+}
+
+func dial(cfg *chains.ChainConfig) (config.ChainClient, error) {
+	return clients.GetEthClient(cfg)
+}
+
+func dialSolana(cfg *chains.ChainConfig) (config.SolanaClient, error) {
+	return clients.GetSolanaClient(cfg)
+}
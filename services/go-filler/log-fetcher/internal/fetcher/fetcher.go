@@ -2,35 +2,56 @@ package fetcher
 
 import (
 	"context"
-	"math/big"
+	"fmt"
 	"os"
 	"os/signal"
-	"sync"
 	"syscall"
+	"time"
 
-	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/chains"
-	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/listener"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/config"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/metrics"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/policy"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/pricing"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/requesters"
 	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/store"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/urfave/cli/v2"
-	"gopkg.in/yaml.v2"
 )
 
 func Main(ctx *cli.Context) error {
-	log.SetDefault(log.NewLogger(log.NewTerminalHandlerWithLevel(os.Stderr, log.LevelInfo, true)))
+	cfg, paths, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	sources := supportedChains(ctx, cfg)
 
-	networksFile, err := os.ReadFile("log-fetcher/config/networks.yaml")
+	effective, err := config.Redacted(cfg)
 	if err != nil {
-		log.Crit("Failed to read networks file", "error", err)
+		return err
+	}
+	if ctx.Bool("print-config") {
+		fmt.Print(effective)
+		return nil
+	}
+	log.Info("Loaded config", "profile", ctx.String("network-profile"), "files", paths)
+	log.Debug("Effective config", "config", effective)
+
+	if err := checkConfig(ctx.Context, cfg, sources); err != nil {
+		return err
+	}
+
+	if ctx.String("mongo-uri") == "" {
+		return fmt.Errorf("mongo-uri is required")
 	}
 
-	// expand environment variables
-	networksFile = []byte(os.ExpandEnv(string(networksFile)))
+	if addr := ctx.String("metrics-addr"); addr != "" {
+		server := metrics.Serve(addr)
+		defer server.Close()
+	}
 
-	var cfg chains.NetworksConfig
-	err = yaml.Unmarshal(networksFile, &cfg)
+	oracle, err := newPriceOracle(ctx)
 	if err != nil {
-		log.Crit("Failed to unmarshal networks file", "error", err)
+		log.Crit("Failed to create price oracle", "error", err)
 	}
 
 	queue, err := store.NewQueue(ctx)
@@ -39,36 +60,34 @@ func Main(ctx *cli.Context) error {
 	}
 	defer queue.Close()
 
-	var wg sync.WaitGroup
 	stopped, stop := context.WithCancel(context.Background())
+	defer stop()
 
-	for _, chainId := range ctx.StringSlice("supported-chains") {
-		chainIdBigInt, ok := new(big.Int).SetString(chainId, 10)
-		if !ok {
-			log.Crit("Failed to convert chainId to big.Int", "chainId", chainId)
-		}
-
-		checkpoint, err := queue.ReadCheckpoint(chainId)
-		if err != nil {
-			log.Crit("Failed to read checkpoint", "error", err)
-		}
+	guard, lists, err := newRequesterGuard(ctx)
+	if err != nil {
+		log.Crit("Failed to create requester guard", "error", err)
+	}
 
-		l, err := listener.NewListener(chainIdBigInt, cfg.Networks, queue, checkpoint+1)
+	var rules *policy.Engine
+	watched := paths
+	if lists != nil {
+		watched = append(watched, lists.Path())
+	}
+	if path := ctx.String("policy-file"); path != "" {
+		rules, err = policy.Load(path)
 		if err != nil {
-			log.Crit("Failed to create listener", "error", err)
+			log.Crit("Failed to load policy", "error", err)
 		}
+		watched = append(watched, path)
+	}
 
-		wg.Add(1)
-		err = l.Start()
-		if err != nil {
-			log.Crit("Failed to start listener", "error", err)
-		}
+	s := newSources(sources, paths, cfg, queue, oracle, guard, lists, rules)
+	if err := s.start(); err != nil {
+		log.Crit("Failed to start listeners", "error", err)
+	}
 
-		go func() {
-			defer wg.Done()
-			<-stopped.Done()
-			l.Stop()
-		}()
+	if err := s.watch(stopped, watched); err != nil {
+		log.Crit("Failed to watch config", "error", err)
 	}
 
 	// Handle signals to initiate shutdown
@@ -78,7 +97,52 @@ func Main(ctx *cli.Context) error {
 
 	log.Info("Shutting down...")
 	stop()
-	wg.Wait()
+	s.stop()
 
 	return nil
 }
+
+func newPriceOracle(ctx *cli.Context) (pricing.PriceOracle, error) {
+	var oracle pricing.PriceOracle
+	switch kind := ctx.String("price-oracle"); kind {
+	case "static":
+		static, err := pricing.LoadStaticOracle(ctx.String("prices-file"))
+		if err != nil {
+			return nil, err
+		}
+		oracle = static
+	case "http":
+		if ctx.String("price-oracle-url") == "" {
+			return nil, fmt.Errorf("price-oracle-url is required for the http price oracle")
+		}
+		oracle = pricing.NewHTTPOracle(ctx.String("price-oracle-url"), 5*time.Second)
+	default:
+		return nil, fmt.Errorf("unknown price oracle %q", kind)
+	}
+
+	return pricing.NewCachedOracle(oracle, ctx.Duration("price-cache-ttl"), ctx.Duration("price-max-age")), nil
+}
+
+// newRequesterGuard returns the requester guard and the lists it checks, nil
+// if no lists file is set. The lists are reloaded along with the config.
+func newRequesterGuard(ctx *cli.Context) (*requesters.Guard, *requesters.Lists, error) {
+	var limits []requesters.Limit
+	for _, s := range ctx.StringSlice("requester-rate-limits") {
+		limit, err := requesters.ParseLimit(s)
+		if err != nil {
+			return nil, nil, err
+		}
+		limits = append(limits, limit)
+	}
+
+	var lists *requesters.Lists
+	if path := ctx.String("requester-lists-file"); path != "" {
+		var err error
+		lists, err = requesters.LoadLists(path)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return requesters.NewGuard(lists, requesters.NewLimiter(limits)), lists, nil
+}
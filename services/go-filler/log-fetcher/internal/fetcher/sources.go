@@ -0,0 +1,215 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"slices"
+	"sync"
+	"syscall"
+
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/chains"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/clients"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/config"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/listener"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/policy"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/pricing"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/requesters"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/store"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/validator"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/watch"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// sources runs a listener for each supported chain and keeps them in step with
+// the networks config.
+type sources struct {
+	chainIds []string
+	paths    []string
+	queue    store.Queue
+	oracle   pricing.PriceOracle
+	guard    *requesters.Guard
+	lists    *requesters.Lists
+	rules    *policy.Engine
+	pool     *clients.Pool
+	exposure validator.Stage
+
+	mu         sync.Mutex
+	cfg        *chains.NetworksConfig
+	validators map[string]*validator.Current
+	listeners  map[string]listener.Listener
+}
+
+func newSources(chainIds, paths []string, cfg *chains.NetworksConfig, queue store.Queue, oracle pricing.PriceOracle, guard *requesters.Guard, lists *requesters.Lists, rules *policy.Engine) *sources {
+	pool := clients.NewPool()
+
+	return &sources{
+		chainIds:   chainIds,
+		paths:      paths,
+		queue:      queue,
+		oracle:     oracle,
+		guard:      guard,
+		lists:      lists,
+		rules:      rules,
+		pool:       pool,
+		exposure:   validator.NewExposureCheck(queue, pool),
+		cfg:        cfg,
+		validators: make(map[string]*validator.Current),
+		listeners:  make(map[string]listener.Listener),
+	}
+}
+
+// start starts a listener for every supported chain.
+func (s *sources) start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, chainId := range s.chainIds {
+		v, err := s.validator(s.cfg, chainId)
+		if err != nil {
+			return err
+		}
+		current := validator.NewCurrent(v)
+
+		l, err := s.listener(s.cfg, chainId, current)
+		if err != nil {
+			return err
+		}
+		if err := l.Start(); err != nil {
+			return fmt.Errorf("failed to start listener for chain %s: %v", chainId, err)
+		}
+
+		s.validators[chainId] = current
+		s.listeners[chainId] = l
+	}
+
+	return nil
+}
+
+// watch reloads on changes to the watched files and on SIGHUP until ctx is
+// done.
+func (s *sources) watch(ctx context.Context, paths []string) error {
+	return watch.Files(ctx, paths, func() { s.reload(ctx) }, syscall.SIGHUP)
+}
+
+// reload re-reads the config, policy and requester lists. If the networks changed, every
+// validator switches to the new networks and the listeners of source chains
+// that changed are restarted from their checkpoint. Validators share the
+// pool's clients, so only chains whose node or rpc settings changed are
+// dialed again. An invalid config leaves everything as it was.
+func (s *sources) reload(ctx context.Context) {
+	if s.rules != nil {
+		if err := s.rules.Reload(); err != nil {
+			log.Error("Failed to reload policy, keeping previous rules", "error", err)
+		}
+	}
+	if s.lists != nil {
+		if err := s.lists.Reload(); err != nil {
+			log.Error("Failed to reload requester lists, keeping previous lists", "error", err)
+		}
+	}
+
+	cfg, err := config.Load(s.paths...)
+	if err == nil {
+		err = checkConfig(ctx, cfg, s.chainIds)
+	}
+	if err != nil {
+		log.Error("Failed to reload config, keeping previous config", "error", err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The fetcher may have shut down while the config was being checked.
+	if ctx.Err() != nil {
+		return
+	}
+
+	changed := config.Diff(s.cfg.Networks, cfg.Networks)
+	if len(changed) == 0 {
+		log.Info("Reloaded config, networks unchanged")
+		return
+	}
+	log.Info("Reloaded config", "changed", changed)
+
+	// Requests still being validated against a closed client are deferred.
+	if redial := config.Redial(s.cfg.Networks, cfg.Networks, changed); len(redial) > 0 {
+		s.pool.Close(redial...)
+		log.Info("Closed clients of chains whose node changed", "chains", redial)
+	}
+
+	for _, chainId := range s.chainIds {
+		v, err := s.validator(cfg, chainId)
+		if err != nil {
+			log.Error("Failed to rebuild validator, keeping previous config", "chainId", chainId, "error", err)
+			continue
+		}
+
+		if !slices.Contains(changed, chainId) {
+			s.validators[chainId].Store(v)
+			continue
+		}
+
+		current := validator.NewCurrent(v)
+		l, err := s.listener(cfg, chainId, current)
+		if err != nil {
+			log.Error("Failed to create listener, keeping previous listener", "chainId", chainId, "error", err)
+			continue
+		}
+
+		s.listeners[chainId].Stop()
+		if err := l.Start(); err != nil {
+			log.Error("Failed to restart listener", "chainId", chainId, "error", err)
+		}
+		s.validators[chainId] = current
+		s.listeners[chainId] = l
+		log.Info("Restarted listener", "chainId", chainId)
+	}
+
+	s.cfg = cfg
+}
+
+func (s *sources) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, l := range s.listeners {
+		l.Stop()
+	}
+	s.pool.CloseAll()
+}
+
+func (s *sources) validator(cfg *chains.NetworksConfig, chainId string) (*validator.ValidatorChain, error) {
+	srcChain, err := cfg.Networks.GetChainConfig(parseChainId(chainId))
+	if err != nil {
+		return nil, err
+	}
+
+	return validator.NewValidator(srcChain, cfg.Networks, s.oracle, s.guard, s.rules, s.pool).
+		Use(validator.Economic, "exposure", s.exposure), nil
+}
+
+func (s *sources) listener(cfg *chains.NetworksConfig, chainId string, v validator.Validator) (listener.Listener, error) {
+	checkpoint, err := s.queue.ReadCheckpoint(chainId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %v", err)
+	}
+
+	l, err := listener.NewListener(parseChainId(chainId), cfg.Networks, s.queue, v, checkpoint+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create listener for chain %s: %v", chainId, err)
+	}
+
+	return l, nil
+}
+
+// parseChainId parses a supported chain id. Supported chains have been checked
+// against the config by then, so only configured keys get here.
+func parseChainId(chainId string) *big.Int {
+	id, _ := new(big.Int).SetString(chainId, 10)
+	if id == nil {
+		return new(big.Int)
+	}
+	return id
+}
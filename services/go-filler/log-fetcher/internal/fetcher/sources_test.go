@@ -0,0 +1,56 @@
+package fetcher
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/requesters"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchReloadsRequesterListsOnSIGHUP(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "requesters.yaml")
+	os.WriteFile(path, []byte("blocklist: []\n"), 0o644)
+	requester := common.HexToAddress("0x2222222222222222222222222222222222222222")
+
+	lists, err := requesters.LoadLists(path)
+	assert.NoError(t, err)
+
+	// the config is missing, so only the lists are reloaded
+	s := &sources{paths: []string{filepath.Join(dir, "networks.yaml")}, lists: lists}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	assert.NoError(t, s.watch(ctx, nil))
+
+	os.WriteFile(path, []byte("blocklist: ["+requester.Hex()+"]\n"), 0o644)
+	assert.False(t, lists.Blocked(requester))
+
+	syscall.Kill(syscall.Getpid(), syscall.SIGHUP)
+	assert.Eventually(t, func() bool { return lists.Blocked(requester) }, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestWatchReloadsRequesterListsOnChange(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "requesters.yaml")
+	os.WriteFile(path, []byte("blocklist: []\n"), 0o644)
+	requester := common.HexToAddress("0x2222222222222222222222222222222222222222")
+
+	lists, err := requesters.LoadLists(path)
+	assert.NoError(t, err)
+
+	s := &sources{paths: []string{filepath.Join(dir, "networks.yaml")}, lists: lists}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	assert.NoError(t, s.watch(ctx, []string{path}))
+
+	os.WriteFile(path, []byte("blocklist: ["+requester.Hex()+"]\n"), 0o644)
+	assert.Eventually(t, func() bool { return lists.Blocked(requester) }, 2*time.Second, 10*time.Millisecond)
+}
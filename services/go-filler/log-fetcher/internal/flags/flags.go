@@ -1,48 +1,133 @@
 package flags
 
 import (
+	"time"
+
 	"github.com/urfave/cli/v2"
 )
 
 var (
+	NetworkProfileFlag = &cli.StringFlag{
+		Name:     "network-profile",
+		Usage:    "Network profile providing the base config (devnet, testnet or mainnet)",
+		Value:    "testnet",
+		EnvVars:  []string{"NETWORK_PROFILE"},
+		Required: false,
+	}
+	ConfigFlag = &cli.StringSliceFlag{
+		Name:     "config",
+		Usage:    "Config files merged in order over the network profile if one is set (e.g. environment, local overrides)",
+		EnvVars:  []string{"CONFIG"},
+		Required: false,
+	}
+	PrintConfigFlag = &cli.BoolFlag{
+		Name:     "print-config",
+		Usage:    "Print the effective config with secrets redacted and exit",
+		Required: false,
+	}
+	LogLevelFlag = &cli.StringFlag{
+		Name:     "log-level",
+		Usage:    "Lowest level logged (debug, info, warn or error), debug also logs the effective config",
+		Value:    "info",
+		EnvVars:  []string{"LOG_LEVEL"},
+		Required: false,
+	}
 	MongoUriFlag = &cli.StringFlag{
 		Name:     "mongo-uri",
 		Usage:    "Connection string to MongoDB",
 		EnvVars:  []string{"MONGO_URI"},
-		Required: true,
-	}
-	ArbitrumSepoliaRpcFlag = &cli.StringFlag{
-		Name:     "arbitrum-sepolia-rpc",
-		Usage:    "Arbitrum Sepolia RPC",
-		EnvVars:  []string{"ARBITRUM_SEPOLIA_RPC"},
-		Required: true,
-	}
-	BaseSepoliaRpcFlag = &cli.StringFlag{
-		Name:     "base-sepolia-rpc",
-		Usage:    "Base Sepolia RPC",
-		EnvVars:  []string{"BASE_SEPOLIA_RPC"},
-		Required: true,
-	}
-	OptimismSepoliaRpcFlag = &cli.StringFlag{
-		Name:     "optimism-sepolia-rpc",
-		Usage:    "Optimism Sepolia RPC",
-		EnvVars:  []string{"OPTIMISM_SEPOLIA_RPC"},
-		Required: true,
-	}
-	SepoliaRpcFlag = &cli.StringFlag{
-		Name:     "sepolia-rpc",
-		Usage:    "Sepolia RPC",
-		EnvVars:  []string{"SEPOLIA_RPC"},
-		Required: true,
+		Required: false,
 	}
 	SupportedChainsFlag = &cli.StringSliceFlag{
 		Name:     "supported-chains",
-		Usage:    "Comma separated list of supported chains",
-		Value:    cli.NewStringSlice("421614"),
+		Usage:    "Comma separated list of supported chains, defaults to the profile's",
 		EnvVars:  []string{"SUPPORTED_CHAINS"},
 		Required: false,
 	}
+	MetricsAddrFlag = &cli.StringFlag{
+		Name:     "metrics-addr",
+		Usage:    "Address to serve Prometheus metrics on, disabled if empty",
+		EnvVars:  []string{"METRICS_ADDR"},
+		Required: false,
+	}
+	PriceOracleFlag = &cli.StringFlag{
+		Name:     "price-oracle",
+		Usage:    "Price oracle used to compare rewards and costs across assets (static or http)",
+		Value:    "static",
+		EnvVars:  []string{"PRICE_ORACLE"},
+		Required: false,
+	}
+	PricesFileFlag = &cli.StringFlag{
+		Name:     "prices-file",
+		Usage:    "Prices file for the static price oracle",
+		Value:    "log-fetcher/config/prices.yaml",
+		EnvVars:  []string{"PRICES_FILE"},
+		Required: false,
+	}
+	PriceOracleUrlFlag = &cli.StringFlag{
+		Name:     "price-oracle-url",
+		Usage:    "Base URL of the http price oracle",
+		EnvVars:  []string{"PRICE_ORACLE_URL"},
+		Required: false,
+	}
+	PriceCacheTtlFlag = &cli.DurationFlag{
+		Name:     "price-cache-ttl",
+		Usage:    "How long a fetched price is reused before asking the oracle again",
+		Value:    30 * time.Second,
+		EnvVars:  []string{"PRICE_CACHE_TTL"},
+		Required: false,
+	}
+	RequesterRateLimitsFlag = &cli.StringSliceFlag{
+		Name:     "requester-rate-limits",
+		Usage:    "Comma separated max/window limits on requests per requester, e.g. 20/1m,200/1h",
+		Value:    cli.NewStringSlice("20/1m", "200/1h"),
+		EnvVars:  []string{"REQUESTER_RATE_LIMITS"},
+		Required: false,
+	}
+	RequesterListsFileFlag = &cli.StringFlag{
+		Name:     "requester-lists-file",
+		Usage:    "Requester blocklist and allowlist file, reloaded on change",
+		EnvVars:  []string{"REQUESTER_LISTS_FILE"},
+		Required: false,
+	}
+	PolicyFileFlag = &cli.StringFlag{
+		Name:     "policy-file",
+		Usage:    "Per-route policy rules file, reloaded on SIGHUP",
+		EnvVars:  []string{"POLICY_FILE"},
+		Required: false,
+	}
+	PriceMaxAgeFlag = &cli.DurationFlag{
+		Name:     "price-max-age",
+		Usage:    "Maximum age of a price quote before it is rejected as stale",
+		Value:    5 * time.Minute,
+		EnvVars:  []string{"PRICE_MAX_AGE"},
+		Required: false,
+	}
 )
 
 // Flags contains the list of configuration options available to the binary.
-var Flags = []cli.Flag{MongoUriFlag, ArbitrumSepoliaRpcFlag, BaseSepoliaRpcFlag, OptimismSepoliaRpcFlag, SepoliaRpcFlag, SupportedChainsFlag}
+var Flags = []cli.Flag{NetworkProfileFlag, ConfigFlag, PrintConfigFlag, LogLevelFlag, MongoUriFlag, SupportedChainsFlag, MetricsAddrFlag, PriceOracleFlag, PricesFileFlag, PriceOracleUrlFlag, PriceCacheTtlFlag, PriceMaxAgeFlag, RequesterRateLimitsFlag, RequesterListsFileFlag, PolicyFileFlag}
+
+var (
+	AddressesFileFlag = &cli.StringFlag{
+		Name:  "addresses",
+		Usage: "Deployed addresses by network and contract name",
+		Value: "../../contracts/addresses.json",
+	}
+	BroadcastDirFlag = &cli.StringFlag{
+		Name:  "broadcast",
+		Usage: "Forge broadcast directory, read for <script>/<chain id>/run-latest.json",
+		Value: "../../contracts/broadcast",
+	}
+	ImportFileFlag = &cli.StringFlag{
+		Name:  "file",
+		Usage: "Networks config file to update, defaults to the network profile's",
+	}
+	WriteFlag = &cli.BoolFlag{
+		Name:  "write",
+		Usage: "Write the changes instead of only showing them",
+	}
+)
+
+// ImportFlags contains the options of the config import command.
+var ImportFlags = []cli.Flag{AddressesFileFlag, BroadcastDirFlag, ImportFileFlag, WriteFlag}
@@ -1,10 +1,16 @@
 package handler
 
 import (
+	"errors"
+	"strconv"
+
 	"github.com/base-org/RIP-7755-poc/services/go-filler/bindings"
-	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/chains"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/metrics"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/solana"
 	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/store"
 	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/validator"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	logger "github.com/ethereum/go-ethereum/log"
 )
 
 type Handler interface {
@@ -16,25 +22,107 @@ type handler struct {
 	queue     store.Queue
 }
 
-func NewHandler(srcChain *chains.ChainConfig, networks chains.Networks, queue store.Queue) (Handler, error) {
-	return &handler{validator: validator.NewValidator(srcChain, networks), queue: queue}, nil
+func NewHandler(queue store.Queue, validator validator.Validator) (Handler, error) {
+	return &handler{validator: validator, queue: queue}, nil
 }
 
 func (h *handler) HandleLog(chainId string, log *bindings.RIP7755OutboxCrossChainCallRequested) error {
-	err := h.validator.ValidateLog(log)
+	result, err := h.validator.ValidateLog(log)
 	if err != nil {
+		var vErr *validator.ValidationError
+		if errors.As(err, &vErr) {
+			h.reject(chainId, log, vErr)
+		}
 		return err
 	}
 
-	err = h.queue.Enqueue(log)
-	if err != nil {
-		return err
+	info := store.JobInfo{
+		Source:           result.Source,
+		Destination:      result.Destination,
+		Requester:        result.Requester,
+		RewardAsset:      result.RewardAsset,
+		RewardSymbol:     result.RewardSymbol,
+		RewardAmount:     result.RewardAmount,
+		NormalizedReward: result.NormalizedReward,
+		CallValue:        result.CallValue,
+		TokenValues:      result.TokenValues,
+	}
+	if sim := result.Simulation; sim != nil {
+		info.Simulation = &store.Simulation{
+			Success:      sim.Success,
+			Result:       hexutil.Encode(sim.ReturnData),
+			GasUsed:      sim.GasUsed,
+			RevertReason: sim.RevertReason,
+		}
+	}
+	if r := result.Solana; r != nil {
+		info.Solana = solanaJob(r)
 	}
 
-	err = h.queue.WriteCheckpoint(chainId, log.Raw.BlockNumber)
+	err = h.queue.Enqueue(log, info)
 	if err != nil {
 		return err
 	}
+	h.recordRequester(log, store.OutcomeAccepted)
 
 	return nil
 }
+
+func solanaJob(r *solana.Request) *store.SolanaJob {
+	job := &store.SolanaJob{
+		ProgramId:   r.InboxContract.String(),
+		RequestHash: r.Hash().Hex(),
+		Request:     hexutil.Encode(r.Borsh()),
+	}
+	for _, call := range r.Calls {
+		job.Calls = append(job.Calls, store.SolanaCall{
+			To:    call.To.String(),
+			Data:  hexutil.Encode(call.Data),
+			Value: strconv.FormatUint(call.Value, 10),
+		})
+	}
+
+	return job
+}
+
+func (h *handler) reject(chainId string, log *bindings.RIP7755OutboxCrossChainCallRequested, vErr *validator.ValidationError) {
+	metrics.Counter("validator/rejected/" + string(vErr.Code)).Inc(1)
+
+	switch vErr.Code {
+	case validator.ReasonRequesterBlocked:
+		h.recordRequester(log, store.OutcomeBlocked)
+	case validator.ReasonRateLimited:
+		h.recordRequester(log, store.OutcomeThrottled)
+		// throttled requests aren't worth a rejection record each
+		return
+	case validator.ReasonRequestCanceled, validator.ReasonRequestCompleted, validator.ReasonAlreadyFulfilled:
+		// already settled, nothing to reject
+		return
+	default:
+		h.recordRequester(log, store.OutcomeRejected)
+	}
+
+	r := store.Rejection{
+		RequestHash:   log.RequestHash,
+		SourceChainId: chainId,
+		Code:          string(vErr.Code),
+		Expected:      vErr.Expected,
+		Actual:        vErr.Actual,
+	}
+	if vErr.Required != nil {
+		r.Required = vErr.Required.String()
+	}
+	if vErr.Offered != nil {
+		r.Offered = vErr.Offered.String()
+	}
+
+	if err := h.queue.WriteRejection(r); err != nil {
+		logger.Error("Failed to record rejection", "code", vErr.Code, "error", err)
+	}
+}
+
+func (h *handler) recordRequester(log *bindings.RIP7755OutboxCrossChainCallRequested, outcome store.RequesterOutcome) {
+	if err := h.queue.RecordRequester(log.Request.Requester, outcome); err != nil {
+		logger.Error("Failed to record requester stats", "requester", log.Request.Requester, "error", err)
+	}
+}
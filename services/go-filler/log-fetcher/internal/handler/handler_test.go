@@ -1,10 +1,20 @@
 package handler
 
 import (
+	"context"
 	"errors"
+	"math/big"
 	"testing"
 
 	"github.com/base-org/RIP-7755-poc/services/go-filler/bindings"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/ids"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/metrics"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/simulation"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/solana"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/store"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/validator"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -17,13 +27,13 @@ type QueueMock struct {
 	mock.Mock
 }
 
-func (v *ValidatorMock) ValidateLog(log *bindings.RIP7755OutboxCrossChainCallRequested) error {
+func (v *ValidatorMock) ValidateLog(log *bindings.RIP7755OutboxCrossChainCallRequested) (*validator.Result, error) {
 	args := v.Called(log)
-	return args.Error(0)
+	return args.Get(0).(*validator.Result), args.Error(1)
 }
 
-func (q *QueueMock) Enqueue(log *bindings.RIP7755OutboxCrossChainCallRequested) error {
-	args := q.Called(log)
+func (q *QueueMock) Enqueue(log *bindings.RIP7755OutboxCrossChainCallRequested, info store.JobInfo) error {
+	args := q.Called(log, info)
 	return args.Error(0)
 }
 
@@ -37,20 +47,64 @@ func (q *QueueMock) WriteCheckpoint(checkpointId string, blockNumber uint64) err
 	return args.Error(0)
 }
 
+func (q *QueueMock) WriteRejection(r store.Rejection) error {
+	args := q.Called(r)
+	return args.Error(0)
+}
+
+func (q *QueueMock) RecordRequester(requester common.Address, outcome store.RequesterOutcome) error {
+	args := q.Called(requester, outcome)
+	return args.Error(0)
+}
+
+func (q *QueueMock) Pending(dstChainId string) ([]store.Commitment, error) {
+	args := q.Called(dstChainId)
+	return args.Get(0).([]store.Commitment), args.Error(1)
+}
+
+func (q *QueueMock) Subscribe(ctx context.Context, filter store.Filter) (<-chan store.Event, error) {
+	args := q.Called(ctx, filter)
+	return args.Get(0).(<-chan store.Event), args.Error(1)
+}
+
 func (q *QueueMock) Close() error {
 	args := q.Called()
 	return args.Error(0)
 }
 
+var result = &validator.Result{
+	Source:           ids.EVMChain(big.NewInt(421614)),
+	Destination:      ids.EVMChain(big.NewInt(84532)),
+	Requester:        ids.EVMAccount(big.NewInt(421614), common.HexToAddress("0x1111111111111111111111111111111111111111")),
+	RewardAsset:      common.HexToAddress("0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE"),
+	RewardSymbol:     "ETH",
+	RewardAmount:     big.NewInt(1),
+	NormalizedReward: big.NewInt(1),
+	TokenValues:      map[common.Address]*big.Int{common.HexToAddress("0x036CbD53842c5426634e7929541eC2318f3dCF7e"): big.NewInt(5_000_000)},
+	Simulation:       &simulation.Result{Success: true, ReturnData: []byte{}, GasUsed: 210000},
+}
+
+var info = store.JobInfo{
+	Source:           result.Source,
+	Destination:      result.Destination,
+	Requester:        result.Requester,
+	RewardAsset:      result.RewardAsset,
+	RewardSymbol:     result.RewardSymbol,
+	RewardAmount:     result.RewardAmount,
+	NormalizedReward: result.NormalizedReward,
+	TokenValues:      result.TokenValues,
+	Simulation:       &store.Simulation{Success: true, Result: "0x", GasUsed: 210000},
+}
+
 func TestHandler(t *testing.T) {
 	validatorMock := new(ValidatorMock)
 	queueMock := new(QueueMock)
 
 	log := &bindings.RIP7755OutboxCrossChainCallRequested{}
 
-	validatorMock.On("ValidateLog", log).Return(nil)
-	queueMock.On("Enqueue", log).Return(nil)
-	queueMock.On("WriteCheckpoint", "test", log.Raw.BlockNumber).Return(nil)
+	validatorMock.On("ValidateLog", log).Return(result, nil)
+	queueMock.On("Enqueue", log, info).Return(nil)
+	queueMock.On("RecordRequester", log.Request.Requester, store.OutcomeAccepted).Return(nil)
 	handler := &handler{validator: validatorMock, queue: queueMock}
 
 	err := handler.HandleLog("test", log)
@@ -59,6 +113,40 @@ func TestHandler(t *testing.T) {
 
 	validatorMock.AssertExpectations(t)
 	queueMock.AssertExpectations(t)
+	// the listener writes the checkpoint once it knows nothing before is deferred
+	queueMock.AssertNotCalled(t, "WriteCheckpoint", mock.Anything, mock.Anything)
+}
+
+func TestHandlerStoresSolanaJob(t *testing.T) {
+	validatorMock := new(ValidatorMock)
+	queueMock := new(QueueMock)
+
+	log := &bindings.RIP7755OutboxCrossChainCallRequested{}
+	program, _ := ids.ParsePubkey("2nfLnXeeWyAUBsCT8uskj8nvkk46FiwaaVvDx29zQcue")
+	request := &solana.Request{
+		InboxContract: program,
+		Calls:         []solana.Call{{To: program, Data: []byte{0x01}, Value: 1000}},
+	}
+	solanaResult := *result
+	solanaResult.Solana = request
+	matchesJob := mock.MatchedBy(func(info store.JobInfo) bool {
+		job := info.Solana
+		return job != nil &&
+			job.ProgramId == "2nfLnXeeWyAUBsCT8uskj8nvkk46FiwaaVvDx29zQcue" &&
+			job.RequestHash == request.Hash().Hex() &&
+			job.Request == hexutil.Encode(request.Borsh()) &&
+			assert.ObjectsAreEqual([]store.SolanaCall{{To: "2nfLnXeeWyAUBsCT8uskj8nvkk46FiwaaVvDx29zQcue", Data: "0x01", Value: "1000"}}, job.Calls)
+	})
+
+	validatorMock.On("ValidateLog", log).Return(&solanaResult, nil)
+	queueMock.On("Enqueue", log, matchesJob).Return(nil)
+	queueMock.On("RecordRequester", log.Request.Requester, store.OutcomeAccepted).Return(nil)
+	handler := &handler{validator: validatorMock, queue: queueMock}
+
+	err := handler.HandleLog("test", log)
+
+	assert.NoError(t, err)
+	queueMock.AssertExpectations(t)
 }
 
 func TestHandlerReturnsErrorFromValidator(t *testing.T) {
@@ -67,7 +155,7 @@ func TestHandlerReturnsErrorFromValidator(t *testing.T) {
 
 	log := &bindings.RIP7755OutboxCrossChainCallRequested{}
 
-	validatorMock.On("ValidateLog", log).Return(errors.New("test error"))
+	validatorMock.On("ValidateLog", log).Return((*validator.Result)(nil), errors.New("test error"))
 
 	handler := &handler{validator: validatorMock, queue: queueMock}
 
@@ -76,14 +164,43 @@ func TestHandlerReturnsErrorFromValidator(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestHandlerRecordsValidationRejection(t *testing.T) {
+	validatorMock := new(ValidatorMock)
+	queueMock := new(QueueMock)
+
+	log := &bindings.RIP7755OutboxCrossChainCallRequested{RequestHash: [32]byte{1}}
+	vErr := &validator.ValidationError{Code: validator.ReasonInsufficientReward, Required: big.NewInt(2), Offered: big.NewInt(1)}
+	counter := metrics.Counter("validator/rejected/" + string(validator.ReasonInsufficientReward))
+	before := counter.Snapshot().Count()
+
+	validatorMock.On("ValidateLog", log).Return((*validator.Result)(nil), vErr)
+	queueMock.On("WriteRejection", store.Rejection{
+		RequestHash:   log.RequestHash,
+		SourceChainId: "test",
+		Code:          string(validator.ReasonInsufficientReward),
+		Required:      "2",
+		Offered:       "1",
+	}).Return(nil)
+	queueMock.On("RecordRequester", log.Request.Requester, store.OutcomeRejected).Return(nil)
+
+	handler := &handler{validator: validatorMock, queue: queueMock}
+
+	err := handler.HandleLog("test", log)
+
+	assert.ErrorIs(t, err, validator.ErrInsufficientReward)
+	assert.Equal(t, before+1, counter.Snapshot().Count())
+	queueMock.AssertExpectations(t)
+	queueMock.AssertNotCalled(t, "Enqueue", mock.Anything, mock.Anything)
+}
+
 func TestHandlerReturnsErrorFromQueue(t *testing.T) {
 	validatorMock := new(ValidatorMock)
 	queueMock := new(QueueMock)
 
 	log := &bindings.RIP7755OutboxCrossChainCallRequested{}
 
-	validatorMock.On("ValidateLog", log).Return(nil)
-	queueMock.On("Enqueue", log).Return(errors.New("test error"))
+	validatorMock.On("ValidateLog", log).Return(result, nil)
+	queueMock.On("Enqueue", log, info).Return(errors.New("test error"))
 
 	handler := &handler{validator: validatorMock, queue: queueMock}
 
@@ -92,19 +209,56 @@ func TestHandlerReturnsErrorFromQueue(t *testing.T) {
 	assert.Error(t, err)
 }
 
-func TestHandlerReturnsErrorFromWriteCheckpoint(t *testing.T) {
+func TestHandlerRecordsThrottledRequester(t *testing.T) {
 	validatorMock := new(ValidatorMock)
 	queueMock := new(QueueMock)
 
 	log := &bindings.RIP7755OutboxCrossChainCallRequested{}
+	log.Request.Requester = common.HexToAddress("0x1111111111111111111111111111111111111111")
 
-	validatorMock.On("ValidateLog", log).Return(nil)
-	queueMock.On("Enqueue", log).Return(nil)
-	queueMock.On("WriteCheckpoint", "test", log.Raw.BlockNumber).Return(errors.New("test error"))
+	validatorMock.On("ValidateLog", log).Return((*validator.Result)(nil), &validator.ValidationError{Code: validator.ReasonRateLimited})
+	queueMock.On("RecordRequester", log.Request.Requester, store.OutcomeThrottled).Return(nil)
 
 	handler := &handler{validator: validatorMock, queue: queueMock}
 
 	err := handler.HandleLog("test", log)
 
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, validator.ErrRateLimited)
+	queueMock.AssertExpectations(t)
+	queueMock.AssertNotCalled(t, "WriteRejection", mock.Anything)
+}
+
+func TestHandlerIgnoresRequesterStatsError(t *testing.T) {
+	validatorMock := new(ValidatorMock)
+	queueMock := new(QueueMock)
+
+	log := &bindings.RIP7755OutboxCrossChainCallRequested{}
+
+	validatorMock.On("ValidateLog", log).Return(result, nil)
+	queueMock.On("Enqueue", log, info).Return(nil)
+	queueMock.On("RecordRequester", mock.Anything, mock.Anything).Return(errors.New("test error"))
+
+	handler := &handler{validator: validatorMock, queue: queueMock}
+
+	err := handler.HandleLog("test", log)
+
+	assert.NoError(t, err)
+}
+
+func TestHandlerDropsSettledRequest(t *testing.T) {
+	validatorMock := new(ValidatorMock)
+	queueMock := new(QueueMock)
+
+	log := &bindings.RIP7755OutboxCrossChainCallRequested{}
+
+	validatorMock.On("ValidateLog", log).Return((*validator.Result)(nil), validator.ErrAlreadyFulfilled)
+
+	handler := &handler{validator: validatorMock, queue: queueMock}
+
+	err := handler.HandleLog("test", log)
+
+	assert.ErrorIs(t, err, validator.ErrAlreadyFulfilled)
+	queueMock.AssertNotCalled(t, "WriteRejection", mock.Anything)
+	queueMock.AssertNotCalled(t, "RecordRequester", mock.Anything, mock.Anything)
+	queueMock.AssertNotCalled(t, "Enqueue", mock.Anything, mock.Anything)
 }
@@ -0,0 +1,162 @@
+// Package ids identifies chains and accounts independently of the VM they run
+// on. Chains are CAIP-2 chain ids and accounts CAIP-10 account ids. RRC-7755
+// carries both as bytes32, see GlobalTypes.
+package ids
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Namespaces of the chains we know how to address.
+const (
+	EIP155 = "eip155"
+	Solana = "solana"
+)
+
+var (
+	namespacePattern = regexp.MustCompile(`^[-a-z0-9]{3,8}$`)
+	referencePattern = regexp.MustCompile(`^[-_a-zA-Z0-9]{1,32}$`)
+	addressPattern   = regexp.MustCompile(`^[-.%a-zA-Z0-9]{1,128}$`)
+)
+
+var ErrUnsupportedNamespace = errors.New("unsupported namespace")
+
+// ChainID is a CAIP-2 chain id, e.g. eip155:8453.
+type ChainID struct {
+	Namespace string
+	Reference string
+}
+
+// EVMChain returns the id of the EVM chain with the given chain id.
+func EVMChain(chainId *big.Int) ChainID {
+	if chainId == nil {
+		return ChainID{}
+	}
+	return ChainID{Namespace: EIP155, Reference: chainId.String()}
+}
+
+// ParseChainID parses a CAIP-2 chain id.
+func ParseChainID(s string) (ChainID, error) {
+	namespace, reference, ok := strings.Cut(s, ":")
+	if !ok || !namespacePattern.MatchString(namespace) || !referencePattern.MatchString(reference) {
+		return ChainID{}, fmt.Errorf("invalid CAIP-2 chain id %q", s)
+	}
+	return ChainID{Namespace: namespace, Reference: reference}, nil
+}
+
+func (c ChainID) String() string {
+	if c.IsZero() {
+		return ""
+	}
+	return c.Namespace + ":" + c.Reference
+}
+
+func (c ChainID) IsZero() bool {
+	return c == ChainID{}
+}
+
+// Bytes32 returns the chain id as RRC-7755 carries it, the numeric chain id as
+// a big-endian bytes32, as in bytes32(block.chainid). It fails for chains whose
+// reference isn't a number.
+func (c ChainID) Bytes32() ([32]byte, error) {
+	id, ok := new(big.Int).SetString(c.Reference, 10)
+	if !ok || id.Sign() < 0 || id.BitLen() > 256 {
+		return [32]byte{}, fmt.Errorf("chain %s has no numeric chain id", c)
+	}
+	return common.BigToHash(id), nil
+}
+
+// Account is a CAIP-10 account id, e.g.
+// eip155:8453:0xab16a96D359eC26a11e2C2b3d8f8B8942d5Bfcdb. Address is kept in
+// the chain's own encoding.
+type Account struct {
+	Chain   ChainID
+	Address string
+}
+
+// EVMAccount returns the id of an account on the EVM chain with the given
+// chain id.
+func EVMAccount(chainId *big.Int, addr common.Address) Account {
+	return Account{Chain: EVMChain(chainId), Address: addr.Hex()}
+}
+
+// ParseAccount parses a CAIP-10 account id.
+func ParseAccount(s string) (Account, error) {
+	i := strings.LastIndex(s, ":")
+	if i < 0 {
+		return Account{}, fmt.Errorf("invalid CAIP-10 account id %q", s)
+	}
+	chain, err := ParseChainID(s[:i])
+	if err != nil || !addressPattern.MatchString(s[i+1:]) {
+		return Account{}, fmt.Errorf("invalid CAIP-10 account id %q", s)
+	}
+	return Account{Chain: chain, Address: s[i+1:]}, nil
+}
+
+func (a Account) String() string {
+	if a.Chain.IsZero() {
+		return ""
+	}
+	return a.Chain.String() + ":" + a.Address
+}
+
+// Bytes32 returns the account as RRC-7755 carries it, see
+// GlobalTypes.addressToBytes32.
+func (a Account) Bytes32() ([32]byte, error) {
+	switch a.Chain.Namespace {
+	case EIP155:
+		if !common.IsHexAddress(a.Address) {
+			return [32]byte{}, fmt.Errorf("invalid EVM address %q", a.Address)
+		}
+		return AddressToBytes32(common.HexToAddress(a.Address)), nil
+	case Solana:
+		key, err := ParsePubkey(a.Address)
+		if err != nil {
+			return [32]byte{}, err
+		}
+		return key, nil
+	default:
+		return [32]byte{}, fmt.Errorf("%w %q", ErrUnsupportedNamespace, a.Chain.Namespace)
+	}
+}
+
+// AccountFromBytes32 returns the account on chain that an RRC-7755 bytes32
+// refers to.
+func AccountFromBytes32(chain ChainID, b [32]byte) (Account, error) {
+	switch chain.Namespace {
+	case EIP155:
+		addr, ok := Bytes32ToAddress(b)
+		if !ok {
+			return Account{}, fmt.Errorf("%s is not an EVM address", common.Hash(b).Hex())
+		}
+		return Account{Chain: chain, Address: addr.Hex()}, nil
+	case Solana:
+		return Account{Chain: chain, Address: Pubkey(b).String()}, nil
+	default:
+		return Account{}, fmt.Errorf("%w %q", ErrUnsupportedNamespace, chain.Namespace)
+	}
+}
+
+// AddressToBytes32 left-pads an EVM address to bytes32, see
+// GlobalTypes.addressToBytes32.
+func AddressToBytes32(addr common.Address) [32]byte {
+	return common.BytesToHash(addr.Bytes())
+}
+
+// Bytes32ToAddress converts a bytes32 account back to an EVM address. It reports
+// false if any of the upper 12 bytes are set.
+func Bytes32ToAddress(b [32]byte) (common.Address, bool) {
+	for _, v := range b[:12] {
+		if v != 0 {
+			return common.Address{}, false
+		}
+	}
+
+	return common.BytesToAddress(b[12:]), true
+}
@@ -0,0 +1,80 @@
+package ids
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseChainID(t *testing.T) {
+	chain, err := ParseChainID("eip155:8453")
+	assert.NoError(t, err)
+	assert.Equal(t, EVMChain(big.NewInt(8453)), chain)
+	assert.Equal(t, "eip155:8453", chain.String())
+
+	for _, s := range []string{"", "8453", "eip155:", "e:1", "EIP155:1", "eip155:1:2"} {
+		_, err := ParseChainID(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestChainIDBytes32(t *testing.T) {
+	b, err := EVMChain(big.NewInt(84532)).Bytes32()
+	assert.NoError(t, err)
+	assert.Equal(t, common.BigToHash(big.NewInt(84532)), common.Hash(b))
+
+	_, err = ChainID{Namespace: "cosmos", Reference: "cosmoshub-4"}.Bytes32()
+	assert.Error(t, err)
+}
+
+func TestZeroChainID(t *testing.T) {
+	assert.True(t, EVMChain(nil).IsZero())
+	assert.Equal(t, "", EVMChain(nil).String())
+	assert.Equal(t, "", Account{}.String())
+}
+
+func TestParseAccount(t *testing.T) {
+	addr := common.HexToAddress("0xab16a96D359eC26a11e2C2b3d8f8B8942d5Bfcdb")
+
+	account, err := ParseAccount("eip155:8453:0xab16a96D359eC26a11e2C2b3d8f8B8942d5Bfcdb")
+	assert.NoError(t, err)
+	assert.Equal(t, EVMAccount(big.NewInt(8453), addr), account)
+	assert.Equal(t, "eip155:8453:0xab16a96D359eC26a11e2C2b3d8f8B8942d5Bfcdb", account.String())
+
+	for _, s := range []string{"", "0xab16a96D359eC26a11e2C2b3d8f8B8942d5Bfcdb", "eip155:0xab16", "eip155:8453:", "eip155:8453:0x/1"} {
+		_, err := ParseAccount(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestAccountBytes32(t *testing.T) {
+	addr := common.HexToAddress("0x1234567890123456789012345678901234567890")
+	chain := EVMChain(big.NewInt(1))
+
+	b, err := EVMAccount(big.NewInt(1), addr).Bytes32()
+	assert.NoError(t, err)
+	assert.Equal(t, AddressToBytes32(addr), b)
+
+	account, err := AccountFromBytes32(chain, b)
+	assert.NoError(t, err)
+	assert.Equal(t, EVMAccount(big.NewInt(1), addr), account)
+
+	_, err = AccountFromBytes32(chain, common.HexToHash("0x0100000000000000000000001234567890123456789012345678901234567890"))
+	assert.Error(t, err)
+
+	_, err = Account{Chain: ChainID{Namespace: "cosmos", Reference: "cosmoshub-4"}, Address: "cosmos1abc"}.Bytes32()
+	assert.ErrorIs(t, err, ErrUnsupportedNamespace)
+}
+
+func TestBytes32ToAddress(t *testing.T) {
+	addr := common.HexToAddress("0x1234567890123456789012345678901234567890")
+
+	converted, ok := Bytes32ToAddress(AddressToBytes32(addr))
+	assert.True(t, ok)
+	assert.Equal(t, addr, converted)
+
+	_, ok = Bytes32ToAddress(common.HexToHash("0x0100000000000000000000001234567890123456789012345678901234567890"))
+	assert.False(t, ok)
+}
@@ -0,0 +1,100 @@
+package ids
+
+import (
+	"fmt"
+	"math/big"
+)
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// Pubkey is a Solana account address, written in base58.
+type Pubkey [32]byte
+
+// ParsePubkey parses a base58 Solana address.
+func ParsePubkey(s string) (Pubkey, error) {
+	b, err := decodeBase58(s)
+	if err != nil || len(b) != 32 {
+		return Pubkey{}, fmt.Errorf("invalid Solana address %q", s)
+	}
+	return Pubkey(b), nil
+}
+
+func (p Pubkey) String() string {
+	return encodeBase58(p[:])
+}
+
+func (p Pubkey) IsZero() bool {
+	return p == Pubkey{}
+}
+
+func (p *Pubkey) UnmarshalText(text []byte) error {
+	key, err := ParsePubkey(string(text))
+	if err != nil {
+		return err
+	}
+	*p = key
+	return nil
+}
+
+func (p Pubkey) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// SolanaChain returns the id of the Solana cluster with the given genesis hash.
+// CAIP-2 references Solana clusters by the first 32 characters of the hash.
+func SolanaChain(genesisHash string) ChainID {
+	if len(genesisHash) > 32 {
+		genesisHash = genesisHash[:32]
+	}
+	return ChainID{Namespace: Solana, Reference: genesisHash}
+}
+
+func encodeBase58(b []byte) string {
+	n := new(big.Int).SetBytes(b)
+	radix, mod := big.NewInt(58), new(big.Int)
+
+	var out []byte
+	for n.Sign() > 0 {
+		n.DivMod(n, radix, mod)
+		out = append(out, base58Alphabet[mod.Int64()])
+	}
+	// Each leading zero byte is written as a leading 1.
+	for _, v := range b {
+		if v != 0 {
+			break
+		}
+		out = append(out, base58Alphabet[0])
+	}
+
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
+
+func decodeBase58(s string) ([]byte, error) {
+	if s == "" {
+		return nil, fmt.Errorf("empty base58 string")
+	}
+
+	n, radix := new(big.Int), big.NewInt(58)
+	zeros := 0
+	for i := 0; i < len(s); i++ {
+		digit := -1
+		for j := 0; j < len(base58Alphabet); j++ {
+			if base58Alphabet[j] == s[i] {
+				digit = j
+				break
+			}
+		}
+		if digit < 0 {
+			return nil, fmt.Errorf("invalid base58 character %q", s[i])
+		}
+		if digit == 0 && n.Sign() == 0 {
+			zeros++
+		}
+		n.Mul(n, radix).Add(n, big.NewInt(int64(digit)))
+	}
+
+	return append(make([]byte, zeros), n.Bytes()...), nil
+}
@@ -0,0 +1,41 @@
+package ids
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePubkey(t *testing.T) {
+	key, err := ParsePubkey("TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA")
+	assert.NoError(t, err)
+	assert.Equal(t, common.HexToHash("0x06ddf6e1d765a193d9cbe146ceeb79ac1cb485ed5f5b37913a8cf5857eff00a9"), common.Hash(key))
+	assert.Equal(t, "TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA", key.String())
+
+	system, err := ParsePubkey("11111111111111111111111111111111")
+	assert.NoError(t, err)
+	assert.True(t, system.IsZero())
+	assert.Equal(t, "11111111111111111111111111111111", Pubkey{}.String())
+
+	for _, s := range []string{"", "0OIl", "2nfLnXeeWyAUBsCT8uskj8nvkk46", "0x06ddf6e1d765a193d9cbe146ceeb79ac1cb485ed5f5b37913a8cf5857eff00a9"} {
+		_, err := ParsePubkey(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestSolanaAccount(t *testing.T) {
+	chain := SolanaChain("EtWTRABZaYq6iMfeYKouRu166VU2xqa1wcaWoxPkrZBG")
+	assert.Equal(t, "solana:EtWTRABZaYq6iMfeYKouRu166VU2xqa1", chain.String())
+
+	account, err := ParseAccount("solana:EtWTRABZaYq6iMfeYKouRu166VU2xqa1:2nfLnXeeWyAUBsCT8uskj8nvkk46FiwaaVvDx29zQcue")
+	assert.NoError(t, err)
+	assert.Equal(t, chain, account.Chain)
+
+	b, err := account.Bytes32()
+	assert.NoError(t, err)
+
+	converted, err := AccountFromBytes32(chain, b)
+	assert.NoError(t, err)
+	assert.Equal(t, account, converted)
+}
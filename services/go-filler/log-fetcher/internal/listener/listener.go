@@ -2,6 +2,7 @@ package listener
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"regexp"
@@ -13,6 +14,7 @@ import (
 	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/clients"
 	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/handler"
 	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/store"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/validator"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
@@ -26,26 +28,44 @@ type Listener interface {
 
 type listener struct {
 	outbox        *bindings.RIP7755Outbox
+	client        HeadReader
 	handler       handler.Handler
+	queue         store.Queue
 	logs          chan *bindings.RIP7755OutboxCrossChainCallRequested
 	stop          chan struct{}
 	wg            sync.WaitGroup
 	pollRate      time.Duration
+	filterTimeout time.Duration
+	maxBlockRange uint64
+	confirmations uint64
 	pollReqCh     chan struct{}
 	polling       bool
 	startingBlock uint64
+	checkpoint    uint64
+	deferred      []*bindings.RIP7755OutboxCrossChainCallRequested
 	srcChainId    string
 }
 
+// HeadReader reads the latest block number, up to which polling fetches logs.
+type HeadReader interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+}
+
 var httpRegex = regexp.MustCompile("^http(s)?://")
 
-func NewListener(srcChainId *big.Int, networks chains.Networks, queue store.Queue, startingBlock uint64) (Listener, error) {
+func NewListener(srcChainId *big.Int, networks chains.Networks, queue store.Queue, validator validator.Validator, startingBlock uint64) (Listener, error) {
 	srcChain, err := networks.GetChainConfig(srcChainId)
 	if err != nil {
 		return nil, err
 	}
+	settings := srcChain.Listener.Merge(chains.DefaultListener)
+
+	polling, err := usePolling(settings, srcChain.RpcUrl)
+	if err != nil {
+		return nil, err
+	}
 
-	h, err := handler.NewHandler(srcChain, networks, queue)
+	h, err := handler.NewHandler(queue, validator)
 	if err != nil {
 		return nil, err
 	}
@@ -64,19 +84,52 @@ func NewListener(srcChainId *big.Int, networks chains.Networks, queue store.Queu
 		return nil, fmt.Errorf("failed to create Outbox contract binding: %v", err)
 	}
 
+	startingBlock = max(startingBlock, settings.StartBlock)
+	var checkpoint uint64
+	if startingBlock > 0 {
+		checkpoint = startingBlock - 1
+	}
+
 	return &listener{
 		outbox:        outbox,
+		client:        client,
 		handler:       h,
+		queue:         queue,
 		logs:          make(chan *bindings.RIP7755OutboxCrossChainCallRequested),
 		stop:          make(chan struct{}),
 		pollReqCh:     make(chan struct{}, 1),
-		pollRate:      3 * time.Second,
-		polling:       httpRegex.MatchString(srcChain.RpcUrl),
+		pollRate:      settings.PollInterval,
+		filterTimeout: settings.FilterTimeout,
+		maxBlockRange: settings.MaxBlockRange,
+		confirmations: settings.Confirmations,
+		polling:       polling,
 		startingBlock: startingBlock,
+		checkpoint:    checkpoint,
 		srcChainId:    srcChainId.String(),
 	}, nil
 }
 
+// usePolling picks the transport for a chain. Subscriptions deliver logs as
+// soon as they are mined, so a chain that waits for confirmations is polled.
+func usePolling(settings chains.ListenerConfig, rpcUrl string) (bool, error) {
+	switch settings.Transport {
+	case chains.TransportAuto:
+		return httpRegex.MatchString(rpcUrl) || settings.Confirmations > 0, nil
+	case chains.TransportPolling:
+		return true, nil
+	case chains.TransportWebSocket:
+		if httpRegex.MatchString(rpcUrl) {
+			return false, fmt.Errorf("websocket transport needs a WebSocket RPC")
+		}
+		if settings.Confirmations > 0 {
+			return false, fmt.Errorf("websocket transport can't wait for confirmations")
+		}
+		return false, nil
+	default:
+		return false, fmt.Errorf("unknown transport %q", settings.Transport)
+	}
+}
+
 func (l *listener) Start() error {
 	if l.polling {
 		return pollListener(l)
@@ -101,6 +154,16 @@ func webSocketListener(l *listener) error {
 
 func pollListener(l *listener) error {
 	logger.Info("Polling for logs")
+
+	l.wg.Add(1)
+	go l.poll()
+
+	return nil
+}
+
+func (l *listener) poll() {
+	defer l.wg.Done()
+
 	reqPollAfter := func() {
 		if l.pollRate == 0 {
 			return
@@ -113,42 +176,138 @@ func pollListener(l *listener) error {
 	for {
 		select {
 		case <-l.pollReqCh:
-			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-			logIterator, err := l.outbox.FilterCrossChainCallRequested(&bind.FilterOpts{Context: ctx, Start: l.startingBlock}, [][32]byte{})
-			if err != nil {
-				logger.Error("failed to filter logs", "error", err)
-				cancel()
-				logIterator.Close()
-				reqPollAfter()
-				continue
-			}
+			l.pollLogs()
+			reqPollAfter()
+		case <-l.stop:
+			return
+		}
+	}
+}
+
+// pollLogs handles the logs from the starting block up to the confirmed head,
+// after retrying the logs deferred on earlier polls. The starting block moves
+// past each range once it has been handled, and the checkpoint with it.
+func (l *listener) pollLogs() {
+	ctx, cancel := context.WithTimeout(context.Background(), l.filterTimeout)
+	head, err := l.client.BlockNumber(ctx)
+	cancel()
+	if err != nil {
+		logger.Error("failed to get head block", "error", err)
+		return
+	}
+
+	l.retryDeferred()
+	if l.startingBlock > 0 {
+		l.advance(l.startingBlock - 1)
+	}
+
+	if head < l.confirmations || head-l.confirmations < l.startingBlock {
+		return
+	}
+
+	for _, r := range blockRanges(l.startingBlock, head-l.confirmations, l.maxBlockRange) {
+		if err := l.filterLogs(r[0], r[1]); err != nil {
+			logger.Error("failed to filter logs", "from", r[0], "to", r[1], "error", err)
+			return
+		}
+		l.startingBlock = r[1] + 1
+		l.advance(r[1])
+	}
+}
+
+func (l *listener) filterLogs(from, to uint64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), l.filterTimeout)
+	defer cancel()
+
+	logIterator, err := l.outbox.FilterCrossChainCallRequested(&bind.FilterOpts{Context: ctx, Start: from, End: &to}, [][32]byte{})
+	if err != nil {
+		return err
+	}
+	defer logIterator.Close()
+
+	for logIterator.Next() {
+		l.handle(logIterator.Event)
+	}
 
-			for logIterator.Next() {
-				err := logIterator.Error()
-				if err != nil {
-					logger.Error("error iterating over logs", "error", err)
-					continue
-				}
-
-				log := logIterator.Event
-				err = l.handler.HandleLog(l.srcChainId, log)
-				if err != nil {
-					logger.Error("failed to handle log", "error", err)
-					continue
-				}
+	return logIterator.Error()
+}
+
+// handle passes a log to the handler. Logs it defers, rather than rejects,
+// are kept to be handled again on the next poll or retry.
+func (l *listener) handle(log *bindings.RIP7755OutboxCrossChainCallRequested) {
+	err := l.handler.HandleLog(l.srcChainId, log)
+	if err == nil {
+		return
+	}
+	logger.Error("failed to handle log", "error", err)
+
+	var vErr *validator.ValidationError
+	if !errors.As(err, &vErr) {
+		l.deferred = append(l.deferred, log)
+	}
+}
+
+func (l *listener) retryDeferred() {
+	deferred := l.deferred
+	l.deferred = nil
+	for _, log := range deferred {
+		l.handle(log)
+	}
+}
+
+// advance moves the checkpoint up to block, which has been handled. It stays
+// before the oldest deferred log, so that a restart handles that log again
+// rather than losing it.
+func (l *listener) advance(block uint64) {
+	for _, log := range l.deferred {
+		if log.Raw.BlockNumber <= block {
+			if log.Raw.BlockNumber == 0 {
+				return
 			}
+			block = log.Raw.BlockNumber - 1
+		}
+	}
+	if block <= l.checkpoint {
+		return
+	}
 
-			cancel()
-			logIterator.Close()
-			reqPollAfter()
-		case <-l.stop:
-			return nil
+	if err := l.queue.WriteCheckpoint(l.srcChainId, block); err != nil {
+		logger.Error("failed to write checkpoint", "block", block, "error", err)
+		return
+	}
+	l.checkpoint = block
+}
+
+// blockRanges splits [from, to] into inclusive ranges of at most size blocks,
+// or returns it whole if size is 0.
+func blockRanges(from, to, size uint64) [][2]uint64 {
+	if size == 0 {
+		return [][2]uint64{{from, to}}
+	}
+
+	var ranges [][2]uint64
+	for start := from; ; start += size {
+		if to-start < size {
+			return append(ranges, [2]uint64{start, to})
 		}
+		ranges = append(ranges, [2]uint64{start, start + size - 1})
 	}
 }
 
+// loop handles the logs of a subscription as they arrive and retries the
+// deferred ones every poll interval. Other logs of a block may still follow,
+// so the checkpoint only moves up to the block before the latest log.
 func (l *listener) loop(sub ethereum.Subscription) {
 	defer l.wg.Done()
+
+	var retry <-chan time.Time
+	if l.pollRate > 0 {
+		ticker := time.NewTicker(l.pollRate)
+		defer ticker.Stop()
+		retry = ticker.C
+	}
+
+	var latest uint64
 	for {
 		select {
 		case err := <-sub.Err():
@@ -158,9 +317,18 @@ func (l *listener) loop(sub ethereum.Subscription) {
 			logger.Info("Log Block Number", "blockNumber", log.Raw.BlockNumber)
 			logger.Info("Log Index", "index", log.Raw.Index)
 
-			err := l.handler.HandleLog(l.srcChainId, log)
-			if err != nil {
-				logger.Error("Error handling log", "error", err)
+			l.handle(log)
+			latest = max(latest, log.Raw.BlockNumber)
+			if latest > 0 {
+				l.advance(latest - 1)
+			}
+		case <-retry:
+			if len(l.deferred) == 0 {
+				continue
+			}
+			l.retryDeferred()
+			if latest > 0 {
+				l.advance(latest - 1)
 			}
 		case <-l.stop:
 			sub.Unsubscribe()
@@ -175,5 +343,10 @@ func (l *listener) Stop() {
 }
 
 func (l *listener) reqPoll() {
-	l.pollReqCh <- struct{}{}
+	// A poll already requested covers this one, and none are handled once
+	// the listener is stopped.
+	select {
+	case l.pollReqCh <- struct{}{}:
+	default:
+	}
 }
@@ -1,13 +1,24 @@
 package listener
 
 import (
+	"context"
+	"errors"
+	"math"
 	"math/big"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/base-org/RIP-7755-poc/services/go-filler/bindings"
 	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/chains"
 	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/store"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/validator"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 var networksCfg chains.NetworksConfig = chains.NetworksConfig{
@@ -24,10 +35,221 @@ var networksCfg chains.NetworksConfig = chains.NetworksConfig{
 var queue store.Queue
 
 func TestNewListener(t *testing.T) {
-	l, err := NewListener(big.NewInt(421614), networksCfg.Networks, queue, 0)
+	l, err := NewListener(big.NewInt(421614), networksCfg.Networks, queue, nil, 0)
 	if err != nil {
 		t.Fatalf("Failed to create listener: %v", err)
 	}
 
 	assert.NotNil(t, l)
 }
+
+func TestNewListenerSettings(t *testing.T) {
+	networks := chains.Networks{
+		"84532": chains.ChainConfig{
+			RpcUrl:    "https://base-sepolia.example.com",
+			Contracts: &chains.Contracts{Outbox: common.HexToAddress("0xD7a5A114A07cC4B5ebd9C5e1cD1136a99fFA3d68")},
+			Listener: chains.ListenerConfig{
+				PollInterval:  time.Second,
+				MaxBlockRange: 2000,
+				Confirmations: 5,
+				StartBlock:    100,
+			},
+		},
+	}
+
+	l, err := NewListener(big.NewInt(84532), networks, queue, nil, 50)
+	assert.NoError(t, err)
+
+	settings := l.(*listener)
+	assert.Equal(t, time.Second, settings.pollRate)
+	assert.Equal(t, 10*time.Second, settings.filterTimeout)
+	assert.Equal(t, uint64(2000), settings.maxBlockRange)
+	assert.Equal(t, uint64(5), settings.confirmations)
+	assert.Equal(t, uint64(100), settings.startingBlock)
+	assert.Equal(t, uint64(99), settings.checkpoint)
+	assert.True(t, settings.polling)
+
+	// a checkpoint past the start block wins
+	l, err = NewListener(big.NewInt(84532), networks, queue, nil, 150)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(150), l.(*listener).startingBlock)
+}
+
+func TestUsePolling(t *testing.T) {
+	testCases := []struct {
+		name     string
+		settings chains.ListenerConfig
+		rpcUrl   string
+		polling  bool
+		err      string
+	}{
+		{"auto http", chains.ListenerConfig{Transport: chains.TransportAuto}, "https://rpc.example.com", true, ""},
+		{"auto websocket", chains.ListenerConfig{Transport: chains.TransportAuto}, "wss://rpc.example.com", false, ""},
+		{"auto with confirmations", chains.ListenerConfig{Transport: chains.TransportAuto, Confirmations: 1}, "wss://rpc.example.com", true, ""},
+		{"polling over websocket", chains.ListenerConfig{Transport: chains.TransportPolling}, "wss://rpc.example.com", true, ""},
+		{"websocket over http", chains.ListenerConfig{Transport: chains.TransportWebSocket}, "https://rpc.example.com", false, "needs a WebSocket RPC"},
+		{"websocket with confirmations", chains.ListenerConfig{Transport: chains.TransportWebSocket, Confirmations: 1}, "wss://rpc.example.com", false, "can't wait for confirmations"},
+		{"unknown", chains.ListenerConfig{Transport: "grpc"}, "wss://rpc.example.com", false, "unknown transport"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			polling, err := usePolling(tc.settings, tc.rpcUrl)
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.polling, polling)
+		})
+	}
+}
+
+func TestBlockRanges(t *testing.T) {
+	assert.Equal(t, [][2]uint64{{10, 20}}, blockRanges(10, 20, 0))
+	assert.Equal(t, [][2]uint64{{10, 14}, {15, 19}, {20, 20}}, blockRanges(10, 20, 5))
+	assert.Equal(t, [][2]uint64{{10, 10}}, blockRanges(10, 10, 5))
+	assert.Equal(t, [][2]uint64{{math.MaxUint64 - 1, math.MaxUint64}}, blockRanges(math.MaxUint64-1, math.MaxUint64, 5))
+}
+
+// fakeChain serves the Outbox logs of a chain whose head is head.
+type fakeChain struct {
+	bind.ContractBackend
+	head uint64
+	logs []types.Log
+}
+
+func (c *fakeChain) BlockNumber(ctx context.Context) (uint64, error) {
+	return c.head, nil
+}
+
+func (c *fakeChain) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	var logs []types.Log
+	for _, log := range c.logs {
+		if log.BlockNumber >= q.FromBlock.Uint64() && log.BlockNumber <= q.ToBlock.Uint64() {
+			logs = append(logs, log)
+		}
+	}
+	return logs, nil
+}
+
+type HandlerMock struct {
+	mock.Mock
+}
+
+func (h *HandlerMock) HandleLog(chainId string, log *bindings.RIP7755OutboxCrossChainCallRequested) error {
+	args := h.Called(chainId, common.Hash(log.RequestHash))
+	return args.Error(0)
+}
+
+// requestedLog encodes a CrossChainCallRequested log for requestHash.
+func requestedLog(t *testing.T, blockNumber uint64, requestHash common.Hash) types.Log {
+	outboxABI, err := bindings.RIP7755OutboxMetaData.GetAbi()
+	assert.NoError(t, err)
+	event := outboxABI.Events["CrossChainCallRequested"]
+
+	zero := big.NewInt(0)
+	data, err := event.Inputs.NonIndexed().Pack(bindings.CrossChainRequest{
+		Calls:                []bindings.Call{},
+		DestinationChainId:   zero,
+		RewardAmount:         zero,
+		FinalityDelaySeconds: zero,
+		Nonce:                zero,
+		Expiry:               zero,
+		PrecheckData:         []byte{},
+	})
+	assert.NoError(t, err)
+
+	return types.Log{Topics: []common.Hash{event.ID, requestHash}, Data: data, BlockNumber: blockNumber}
+}
+
+func TestPollLogsHandlesEachLogOnce(t *testing.T) {
+	first, second, deferred := common.HexToHash("0x01"), common.HexToHash("0x02"), common.HexToHash("0x03")
+	chain := &fakeChain{head: 12, logs: []types.Log{requestedLog(t, 10, first), requestedLog(t, 11, deferred)}}
+	outbox, err := bindings.NewRIP7755Outbox(common.Address{}, chain)
+	assert.NoError(t, err)
+
+	handlerMock := new(HandlerMock)
+	handlerMock.On("HandleLog", "84532", first).Return(nil).Once()
+	handlerMock.On("HandleLog", "84532", deferred).Return(errors.New("node unavailable")).Once()
+	handlerMock.On("HandleLog", "84532", deferred).Return(nil).Once()
+	handlerMock.On("HandleLog", "84532", second).Return(validator.ErrUnknownInbox).Once()
+	checkpoints := &checkpointQueue{}
+	l := &listener{outbox: outbox, client: chain, handler: handlerMock, queue: checkpoints, filterTimeout: time.Second, confirmations: 2, startingBlock: 10, checkpoint: 9, srcChainId: "84532"}
+
+	l.pollLogs()
+	assert.Equal(t, uint64(11), l.startingBlock)
+
+	chain.head = 20
+	chain.logs = append(chain.logs, requestedLog(t, 15, second))
+	l.pollLogs()
+	// the log deferred at block 11 holds the checkpoint back
+	assert.Equal(t, []uint64{10}, checkpoints.written())
+	l.pollLogs()
+
+	assert.Equal(t, uint64(19), l.startingBlock)
+	assert.Empty(t, l.deferred)
+	assert.Equal(t, []uint64{10, 18}, checkpoints.written())
+	handlerMock.AssertExpectations(t)
+}
+
+// checkpointQueue records the checkpoints written for a chain.
+type checkpointQueue struct {
+	store.Queue
+	mu     sync.Mutex
+	blocks []uint64
+}
+
+func (q *checkpointQueue) WriteCheckpoint(checkpointId string, blockNumber uint64) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.blocks = append(q.blocks, blockNumber)
+	return nil
+}
+
+func (q *checkpointQueue) written() []uint64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return append([]uint64(nil), q.blocks...)
+}
+
+type subscriptionStub struct {
+	err chan error
+}
+
+func (s *subscriptionStub) Err() <-chan error { return s.err }
+func (s *subscriptionStub) Unsubscribe()      {}
+
+func TestLoopRetriesDeferredLogs(t *testing.T) {
+	handlerMock := new(HandlerMock)
+	handlerMock.On("HandleLog", "84532", common.HexToHash("0x01")).Return(errors.New("node unavailable")).Once()
+	handlerMock.On("HandleLog", "84532", common.HexToHash("0x01")).Return(nil).Once()
+	handlerMock.On("HandleLog", "84532", common.HexToHash("0x02")).Return(nil).Once()
+	checkpoints := &checkpointQueue{}
+	l := &listener{
+		handler:    handlerMock,
+		queue:      checkpoints,
+		logs:       make(chan *bindings.RIP7755OutboxCrossChainCallRequested),
+		stop:       make(chan struct{}),
+		pollRate:   10 * time.Millisecond,
+		srcChainId: "84532",
+	}
+	logAt := func(blockNumber uint64, requestHash byte) *bindings.RIP7755OutboxCrossChainCallRequested {
+		return &bindings.RIP7755OutboxCrossChainCallRequested{RequestHash: [32]byte{31: requestHash}, Raw: types.Log{BlockNumber: blockNumber}}
+	}
+
+	l.wg.Add(1)
+	go l.loop(&subscriptionStub{err: make(chan error)})
+	l.logs <- logAt(5, 0x01)
+	l.logs <- logAt(7, 0x02)
+
+	assert.Eventually(t, func() bool {
+		written := checkpoints.written()
+		return len(written) > 0 && written[len(written)-1] == 6
+	}, time.Second, 5*time.Millisecond)
+	l.Stop()
+
+	// block 5 was deferred, so nothing past it was written before the retry
+	assert.Equal(t, []uint64{4, 6}, checkpoints.written())
+	handlerMock.AssertExpectations(t)
+}
@@ -0,0 +1,35 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/metrics/prometheus"
+)
+
+// Registry holds every metric reported by the log fetcher.
+var Registry = metrics.NewRegistry()
+
+// Counter returns the counter registered under name, creating it if needed.
+// Counters are always recorded, independently of geth's global metrics switch.
+func Counter(name string) metrics.Counter {
+	return metrics.GetOrRegisterCounterForced(name, Registry)
+}
+
+// Serve exposes the registry in Prometheus format on addr until the server
+// fails or is closed.
+func Serve(addr string) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", prometheus.Handler(Registry))
+
+	server := &http.Server{Addr: addr, Handler: mux}
+	go func() {
+		log.Info("Serving metrics", "addr", addr)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Error("Metrics server failed", "error", err)
+		}
+	}()
+
+	return server
+}
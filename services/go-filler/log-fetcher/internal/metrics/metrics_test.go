@@ -0,0 +1,14 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCounter(t *testing.T) {
+	Counter("test/counter").Inc(2)
+	Counter("test/counter").Inc(1)
+
+	assert.Equal(t, int64(3), Counter("test/counter").Snapshot().Count())
+}
@@ -0,0 +1,263 @@
+package policy
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/attributes"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/provers"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"gopkg.in/yaml.v2"
+)
+
+const Any = "*"
+
+// Config is the rules file. Requests are held to the first route matching
+// their source and destination chains. Requests matching no route are
+// accepted unless Default is reject.
+type Config struct {
+	Default string  `yaml:"default"`
+	Routes  []Route `yaml:"routes"`
+}
+
+// Route is the acceptance policy for requests from Source to Destination,
+// chain ids or * for any chain. Unset fields don't restrict requests.
+// MaxValue caps the total call value, Tokens lists accepted reward symbols and
+// RequiredAttributes names attributes (precheck, nonce, reward, delay,
+// requester, l2Oracle) or hex selectors the request must carry.
+type Route struct {
+	Name               string           `yaml:"name"`
+	Source             string           `yaml:"source"`
+	Destination        string           `yaml:"destination"`
+	Provers            []provers.Prover `yaml:"provers"`
+	MinMarginBps       uint64           `yaml:"min-margin-bps"`
+	MaxValue           *big.Int         `yaml:"max-value"`
+	Tokens             []string         `yaml:"tokens"`
+	RequiredAttributes []string         `yaml:"required-attributes"`
+
+	selectors [][4]byte
+}
+
+// Input is what a request is judged on. Cost and RewardValue, both in the
+// source chain's native asset, are only needed for the margin check.
+type Input struct {
+	SourceChainId      string
+	DestinationChainId string
+	Prover             provers.Prover
+	CallValue          *big.Int
+	RewardSymbol       string
+	Attributes         attributes.Attributes
+	Cost               *big.Int
+	RewardValue        *big.Int
+}
+
+type Code string
+
+const (
+	CodeNoRoute          Code = "no_route"
+	CodeProver           Code = "prover"
+	CodeMaxValue         Code = "max_value"
+	CodeToken            Code = "token"
+	CodeMissingAttribute Code = "missing_attribute"
+	CodeMargin           Code = "margin"
+)
+
+// Violation explains why a request breaks a route's policy.
+type Violation struct {
+	Route    string
+	Code     Code
+	Actual   string
+	Required *big.Int
+	Offered  *big.Int
+}
+
+func (v *Violation) Error() string {
+	msg := fmt.Sprintf("route %s: %s", v.Route, v.Code)
+	if v.Actual != "" {
+		msg += " " + v.Actual
+	}
+	if v.Required != nil || v.Offered != nil {
+		msg += fmt.Sprintf(" required %s, offered %s", v.Required, v.Offered)
+	}
+	return msg
+}
+
+var attributeSelectors = map[string][4]byte{
+	"precheck":  attributes.PrecheckSelector,
+	"nonce":     attributes.NonceSelector,
+	"reward":    attributes.RewardSelector,
+	"delay":     attributes.DelaySelector,
+	"requester": attributes.RequesterSelector,
+	"l2oracle":  attributes.L2OracleSelector,
+}
+
+// Engine evaluates requests against the current rules. A nil Engine accepts
+// every request.
+type Engine struct {
+	path   string
+	config atomic.Pointer[Config]
+}
+
+func NewEngine(cfg Config) (*Engine, error) {
+	if err := cfg.compile(); err != nil {
+		return nil, err
+	}
+
+	e := &Engine{}
+	e.config.Store(&cfg)
+	return e, nil
+}
+
+// Load reads the rules from path. Call Reload to pick up changes.
+func Load(path string) (*Engine, error) {
+	cfg, err := read(path)
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := NewEngine(cfg)
+	if err != nil {
+		return nil, err
+	}
+	e.path = path
+	return e, nil
+}
+
+// Reload re-reads the rules file. The current rules stay in place if the file
+// is invalid.
+func (e *Engine) Reload() error {
+	cfg, err := read(e.path)
+	if err != nil {
+		return err
+	}
+	if err := cfg.compile(); err != nil {
+		return err
+	}
+
+	e.config.Store(&cfg)
+	return nil
+}
+
+// Evaluate returns the violation of the first route matching in, or nil if
+// the request is acceptable.
+func (e *Engine) Evaluate(in Input) *Violation {
+	if e == nil {
+		return nil
+	}
+
+	cfg := e.config.Load()
+	for i := range cfg.Routes {
+		route := &cfg.Routes[i]
+		if route.matches(in.SourceChainId, in.DestinationChainId) {
+			return route.evaluate(in)
+		}
+	}
+
+	if cfg.Default == "reject" {
+		return &Violation{Route: in.SourceChainId + "->" + in.DestinationChainId, Code: CodeNoRoute}
+	}
+	return nil
+}
+
+func (r *Route) matches(src, dst string) bool {
+	return (r.Source == "" || r.Source == Any || r.Source == src) &&
+		(r.Destination == "" || r.Destination == Any || r.Destination == dst)
+}
+
+func (r *Route) evaluate(in Input) *Violation {
+	if len(r.Provers) > 0 && !contains(r.Provers, in.Prover) {
+		return &Violation{Route: r.Name, Code: CodeProver, Actual: string(in.Prover)}
+	}
+
+	if r.MaxValue != nil && in.CallValue != nil && in.CallValue.Cmp(r.MaxValue) > 0 {
+		return &Violation{Route: r.Name, Code: CodeMaxValue, Required: r.MaxValue, Offered: in.CallValue}
+	}
+
+	if len(r.Tokens) > 0 && !contains(r.Tokens, in.RewardSymbol) {
+		return &Violation{Route: r.Name, Code: CodeToken, Actual: in.RewardSymbol}
+	}
+
+	for i, selector := range r.selectors {
+		if _, ok := in.Attributes.Locate(selector); !ok {
+			return &Violation{Route: r.Name, Code: CodeMissingAttribute, Actual: r.RequiredAttributes[i]}
+		}
+	}
+
+	if r.MinMarginBps > 0 && in.Cost != nil && in.RewardValue != nil {
+		required := new(big.Int).Mul(in.Cost, new(big.Int).SetUint64(10_000+r.MinMarginBps))
+		required.Add(required, big.NewInt(9_999)).Div(required, big.NewInt(10_000))
+		if in.RewardValue.Cmp(required) < 0 {
+			return &Violation{Route: r.Name, Code: CodeMargin, Required: required, Offered: in.RewardValue}
+		}
+	}
+
+	return nil
+}
+
+// compile checks the rules and resolves attribute names to selectors.
+func (c *Config) compile() error {
+	if c.Default != "" && c.Default != "accept" && c.Default != "reject" {
+		return fmt.Errorf("invalid default %q, want accept or reject", c.Default)
+	}
+
+	for i := range c.Routes {
+		route := &c.Routes[i]
+		if route.Name == "" {
+			route.Name = orAny(route.Source) + "->" + orAny(route.Destination)
+		}
+
+		route.selectors = make([][4]byte, 0, len(route.RequiredAttributes))
+		for _, name := range route.RequiredAttributes {
+			selector, err := parseAttribute(name)
+			if err != nil {
+				return fmt.Errorf("route %s: %v", route.Name, err)
+			}
+			route.selectors = append(route.selectors, selector)
+		}
+	}
+
+	return nil
+}
+
+func parseAttribute(name string) ([4]byte, error) {
+	if selector, ok := attributeSelectors[strings.ToLower(name)]; ok {
+		return selector, nil
+	}
+
+	b, err := hexutil.Decode(name)
+	if err != nil || len(b) != 4 {
+		return [4]byte{}, fmt.Errorf("unknown attribute %q", name)
+	}
+	return [4]byte(b), nil
+}
+
+func read(path string) (Config, error) {
+	var cfg Config
+
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+
+	err = yaml.Unmarshal(file, &cfg)
+	return cfg, err
+}
+
+func orAny(s string) string {
+	if s == "" {
+		return Any
+	}
+	return s
+}
+
+func contains[T comparable](list []T, v T) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
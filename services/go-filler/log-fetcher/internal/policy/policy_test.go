@@ -0,0 +1,129 @@
+package policy
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/base-org/RIP-7755-poc/services/go-filler/bindings"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/attributes"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/provers"
+	"github.com/stretchr/testify/assert"
+)
+
+var input = Input{
+	SourceChainId:      "421614",
+	DestinationChainId: "84532",
+	Prover:             provers.OPStackProver,
+	CallValue:          big.NewInt(1_000),
+	RewardSymbol:       "ETH",
+	Attributes:         attributes.FromRequest(&bindings.CrossChainRequest{}),
+	Cost:               big.NewInt(100),
+	RewardValue:        big.NewInt(120),
+}
+
+func newEngine(t *testing.T, routes ...Route) *Engine {
+	e, err := NewEngine(Config{Routes: routes})
+	assert.NoError(t, err)
+	return e
+}
+
+func TestEvaluateAccepts(t *testing.T) {
+	e := newEngine(t, Route{
+		Source:             "421614",
+		Destination:        "84532",
+		Provers:            []provers.Prover{provers.OPStackProver},
+		MinMarginBps:       2_000,
+		MaxValue:           big.NewInt(1_000),
+		Tokens:             []string{"ETH"},
+		RequiredAttributes: []string{"nonce", "0xa362e5db"},
+	})
+
+	assert.Nil(t, e.Evaluate(input))
+}
+
+func TestEvaluateViolations(t *testing.T) {
+	testCases := []struct {
+		name  string
+		route Route
+		code  Code
+	}{
+		{"prover", Route{Provers: []provers.Prover{provers.HashiProver}}, CodeProver},
+		{"max value", Route{MaxValue: big.NewInt(999)}, CodeMaxValue},
+		{"token", Route{Tokens: []string{"USDC"}}, CodeToken},
+		{"attribute", Route{RequiredAttributes: []string{"precheck"}}, CodeMissingAttribute},
+		{"margin", Route{MinMarginBps: 2_001}, CodeMargin},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			violation := newEngine(t, tc.route).Evaluate(input)
+
+			assert.NotNil(t, violation)
+			assert.Equal(t, tc.code, violation.Code)
+			assert.Equal(t, "*->*", violation.Route)
+		})
+	}
+}
+
+func TestEvaluateFirstMatchingRoute(t *testing.T) {
+	e := newEngine(t,
+		Route{Name: "other", Source: "84532", Tokens: []string{"USDC"}},
+		Route{Name: "arbitrum", Source: "421614", Tokens: []string{"USDC"}},
+		Route{Name: "any", Source: Any},
+	)
+
+	violation := e.Evaluate(input)
+
+	assert.Equal(t, "arbitrum", violation.Route)
+}
+
+func TestEvaluateDefault(t *testing.T) {
+	accept, err := NewEngine(Config{Routes: []Route{{Source: "1"}}})
+	assert.NoError(t, err)
+	assert.Nil(t, accept.Evaluate(input))
+
+	reject, err := NewEngine(Config{Default: "reject", Routes: []Route{{Source: "1"}}})
+	assert.NoError(t, err)
+	assert.Equal(t, CodeNoRoute, reject.Evaluate(input).Code)
+}
+
+func TestEvaluateNilEngine(t *testing.T) {
+	var e *Engine
+
+	assert.Nil(t, e.Evaluate(input))
+}
+
+func TestNewEngineInvalid(t *testing.T) {
+	_, err := NewEngine(Config{Default: "maybe"})
+	assert.Error(t, err)
+
+	_, err = NewEngine(Config{Routes: []Route{{RequiredAttributes: []string{"bogus"}}}})
+	assert.Error(t, err)
+}
+
+func TestLoadExampleConfig(t *testing.T) {
+	e, err := Load("../../config/policy.yaml")
+
+	assert.NoError(t, err)
+	assert.Len(t, e.config.Load().Routes, 2)
+	assert.Equal(t, big.NewInt(1_000_000_000_000_000_000), e.config.Load().Routes[0].MaxValue)
+}
+
+func TestReloadKeepsRulesOnError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	os.WriteFile(path, []byte("routes:\n  - tokens: [USDC]\n"), 0o644)
+
+	e, err := Load(path)
+	assert.NoError(t, err)
+	assert.Equal(t, CodeToken, e.Evaluate(input).Code)
+
+	os.WriteFile(path, []byte("routes:\n  - required-attributes: [bogus]\n"), 0o644)
+	assert.Error(t, e.Reload())
+	assert.Equal(t, CodeToken, e.Evaluate(input).Code)
+
+	os.WriteFile(path, []byte("routes:\n  - tokens: [ETH]\n"), 0o644)
+	assert.NoError(t, e.Reload())
+	assert.Nil(t, e.Evaluate(input))
+}
@@ -0,0 +1,62 @@
+package pricing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type httpOracle struct {
+	endpoint string
+	client   *http.Client
+}
+
+type httpQuote struct {
+	Symbol    string `json:"symbol"`
+	Price     string `json:"price"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// NewHTTPOracle fetches prices from GET {endpoint}/prices/{symbol}, which must
+// respond with {"symbol": "ETH", "price": "3000.12", "timestamp": <unix seconds>}.
+// Point endpoint at a local mock to run without a price provider.
+func NewHTTPOracle(endpoint string, timeout time.Duration) PriceOracle {
+	return &httpOracle{endpoint: strings.TrimRight(endpoint, "/"), client: &http.Client{Timeout: timeout}}
+}
+
+func (o *httpOracle) Quote(ctx context.Context, symbol string) (Quote, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, o.endpoint+"/prices/"+url.PathEscape(symbol), nil)
+	if err != nil {
+		return Quote{}, err
+	}
+
+	res, err := o.client.Do(req)
+	if err != nil {
+		return Quote{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return Quote{}, fmt.Errorf("%w: %s", ErrUnknownSymbol, symbol)
+	}
+	if res.StatusCode != http.StatusOK {
+		return Quote{}, fmt.Errorf("price oracle returned status %d for %s", res.StatusCode, symbol)
+	}
+
+	var q httpQuote
+	if err := json.NewDecoder(res.Body).Decode(&q); err != nil {
+		return Quote{}, fmt.Errorf("failed to decode quote for %s: %v", symbol, err)
+	}
+
+	price, ok := new(big.Rat).SetString(q.Price)
+	if !ok {
+		return Quote{}, fmt.Errorf("invalid price for %s: %q", symbol, q.Price)
+	}
+
+	return Quote{Symbol: symbol, Price: price, Timestamp: time.Unix(q.Timestamp, 0)}, nil
+}
@@ -0,0 +1,59 @@
+package pricing
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPOracle(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/prices/ETH":
+			w.Write([]byte(`{"symbol":"ETH","price":"3000.25","timestamp":1700000000}`))
+		case "/prices/BAD":
+			w.Write([]byte(`{"symbol":"BAD","price":"n/a","timestamp":1700000000}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	oracle := NewHTTPOracle(server.URL+"/", time.Second)
+
+	t.Run("known symbol", func(t *testing.T) {
+		quote, err := oracle.Quote(context.Background(), "ETH")
+
+		assert.NoError(t, err)
+		assert.Equal(t, big.NewRat(300025, 100), quote.Price)
+		assert.Equal(t, time.Unix(1700000000, 0), quote.Timestamp)
+	})
+
+	t.Run("unknown symbol", func(t *testing.T) {
+		_, err := oracle.Quote(context.Background(), "DOGE")
+
+		assert.ErrorIs(t, err, ErrUnknownSymbol)
+	})
+
+	t.Run("invalid price", func(t *testing.T) {
+		_, err := oracle.Quote(context.Background(), "BAD")
+
+		assert.Error(t, err)
+	})
+}
+
+func TestHTTPOracleServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	_, err := NewHTTPOracle(server.URL, time.Second).Quote(context.Background(), "ETH")
+
+	assert.Error(t, err)
+}
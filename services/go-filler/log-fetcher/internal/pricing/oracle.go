@@ -0,0 +1,110 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+)
+
+var (
+	ErrUnknownSymbol = errors.New("unknown symbol")
+	ErrStaleQuote    = errors.New("stale quote")
+)
+
+// Quote is the USD price of one whole unit of an asset.
+type Quote struct {
+	Symbol    string
+	Price     *big.Rat
+	Timestamp time.Time
+}
+
+type PriceOracle interface {
+	Quote(ctx context.Context, symbol string) (Quote, error)
+}
+
+// Asset identifies an amount's denomination for conversion.
+type Asset struct {
+	Symbol   string
+	Decimals uint8
+}
+
+// Convert converts amount, in the smallest unit of from, to the smallest unit
+// of to. Amounts in the same asset are only rescaled and never hit the oracle.
+func Convert(ctx context.Context, oracle PriceOracle, amount *big.Int, from, to Asset) (*big.Int, error) {
+	value := new(big.Rat).SetInt(amount)
+
+	if from.Symbol != to.Symbol {
+		fromQuote, err := oracle.Quote(ctx, from.Symbol)
+		if err != nil {
+			return nil, err
+		}
+		toQuote, err := oracle.Quote(ctx, to.Symbol)
+		if err != nil {
+			return nil, err
+		}
+		if toQuote.Price.Sign() == 0 {
+			return nil, fmt.Errorf("zero price for %s", to.Symbol)
+		}
+
+		value.Mul(value, fromQuote.Price)
+		value.Quo(value, toQuote.Price)
+	}
+
+	value.Mul(value, pow10(int(to.Decimals)))
+	value.Quo(value, pow10(int(from.Decimals)))
+
+	return new(big.Int).Quo(value.Num(), value.Denom()), nil
+}
+
+type cached struct {
+	oracle PriceOracle
+	ttl    time.Duration
+	maxAge time.Duration
+	now    func() time.Time
+
+	mu     sync.Mutex
+	quotes map[string]cachedQuote
+}
+
+type cachedQuote struct {
+	quote     Quote
+	fetchedAt time.Time
+}
+
+// NewCachedOracle wraps oracle so that each symbol is fetched at most once per
+// ttl, and rejects quotes whose timestamp is older than maxAge.
+func NewCachedOracle(oracle PriceOracle, ttl, maxAge time.Duration) PriceOracle {
+	return &cached{oracle: oracle, ttl: ttl, maxAge: maxAge, now: time.Now, quotes: make(map[string]cachedQuote)}
+}
+
+func (c *cached) Quote(ctx context.Context, symbol string) (Quote, error) {
+	c.mu.Lock()
+	entry, ok := c.quotes[symbol]
+	c.mu.Unlock()
+
+	now := c.now()
+	if !ok || now.Sub(entry.fetchedAt) >= c.ttl {
+		quote, err := c.oracle.Quote(ctx, symbol)
+		if err != nil {
+			return Quote{}, err
+		}
+
+		entry = cachedQuote{quote: quote, fetchedAt: now}
+		c.mu.Lock()
+		c.quotes[symbol] = entry
+		c.mu.Unlock()
+	}
+
+	if c.maxAge > 0 && now.Sub(entry.quote.Timestamp) > c.maxAge {
+		return Quote{}, fmt.Errorf("%w: %s quoted at %s", ErrStaleQuote, symbol, entry.quote.Timestamp.UTC().Format(time.RFC3339))
+	}
+
+	return entry.quote, nil
+}
+
+func pow10(n int) *big.Rat {
+	return new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil))
+}
@@ -0,0 +1,112 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type OracleMock struct {
+	mock.Mock
+}
+
+func (o *OracleMock) Quote(ctx context.Context, symbol string) (Quote, error) {
+	args := o.Called(ctx, symbol)
+	return args.Get(0).(Quote), args.Error(1)
+}
+
+var (
+	eth  = Asset{Symbol: "ETH", Decimals: 18}
+	usdc = Asset{Symbol: "USDC", Decimals: 6}
+)
+
+func TestConvert(t *testing.T) {
+	oracle, err := NewStaticOracle(PricesConfig{Prices: map[string]string{"ETH": "2500", "USDC": "1"}})
+	assert.NoError(t, err)
+
+	// 5000 USDC -> 2 ETH
+	wei, err := Convert(context.Background(), oracle, big.NewInt(5_000_000_000), usdc, eth)
+	assert.NoError(t, err)
+	assert.Equal(t, new(big.Int).Mul(big.NewInt(2), big.NewInt(1e18)), wei)
+
+	// 0.5 ETH -> 1250 USDC
+	units, err := Convert(context.Background(), oracle, big.NewInt(5e17), eth, usdc)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(1_250_000_000), units)
+}
+
+func TestConvertSameAssetSkipsOracle(t *testing.T) {
+	oracle := new(OracleMock)
+
+	amount, err := Convert(context.Background(), oracle, big.NewInt(42), eth, eth)
+
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(42), amount)
+	oracle.AssertNotCalled(t, "Quote", mock.Anything, mock.Anything)
+}
+
+func TestConvertUnknownSymbol(t *testing.T) {
+	oracle, _ := NewStaticOracle(PricesConfig{Prices: map[string]string{"ETH": "2500"}})
+
+	_, err := Convert(context.Background(), oracle, big.NewInt(1), usdc, eth)
+
+	assert.ErrorIs(t, err, ErrUnknownSymbol)
+}
+
+func TestCachedOracleCachesWithinTTL(t *testing.T) {
+	inner := new(OracleMock)
+	now := time.Unix(1_000, 0)
+	inner.On("Quote", mock.Anything, "ETH").Return(Quote{Symbol: "ETH", Price: big.NewRat(2500, 1), Timestamp: now}, nil).Once()
+
+	c := NewCachedOracle(inner, time.Minute, time.Hour).(*cached)
+	c.now = func() time.Time { return now }
+
+	_, err := c.Quote(context.Background(), "ETH")
+	assert.NoError(t, err)
+	_, err = c.Quote(context.Background(), "ETH")
+	assert.NoError(t, err)
+
+	inner.AssertNumberOfCalls(t, "Quote", 1)
+}
+
+func TestCachedOracleRefreshesAfterTTL(t *testing.T) {
+	inner := new(OracleMock)
+	now := time.Unix(1_000, 0)
+	inner.On("Quote", mock.Anything, "ETH").Return(Quote{Symbol: "ETH", Price: big.NewRat(2500, 1), Timestamp: now}, nil)
+
+	c := NewCachedOracle(inner, time.Minute, time.Hour).(*cached)
+	c.now = func() time.Time { return now }
+
+	_, _ = c.Quote(context.Background(), "ETH")
+	now = now.Add(time.Minute)
+	_, _ = c.Quote(context.Background(), "ETH")
+
+	inner.AssertNumberOfCalls(t, "Quote", 2)
+}
+
+func TestCachedOracleRejectsStaleQuote(t *testing.T) {
+	inner := new(OracleMock)
+	now := time.Unix(1_000, 0)
+	inner.On("Quote", mock.Anything, "ETH").Return(Quote{Symbol: "ETH", Price: big.NewRat(2500, 1), Timestamp: now.Add(-10 * time.Minute)}, nil)
+
+	c := NewCachedOracle(inner, time.Minute, 5*time.Minute).(*cached)
+	c.now = func() time.Time { return now }
+
+	_, err := c.Quote(context.Background(), "ETH")
+
+	assert.ErrorIs(t, err, ErrStaleQuote)
+}
+
+func TestCachedOracleReturnsError(t *testing.T) {
+	inner := new(OracleMock)
+	inner.On("Quote", mock.Anything, "ETH").Return(Quote{}, errors.New("error"))
+
+	_, err := NewCachedOracle(inner, time.Minute, time.Hour).Quote(context.Background(), "ETH")
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,38 @@
+package pricing
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadStaticOracle(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prices.yaml")
+	err := os.WriteFile(path, []byte("prices:\n  ETH: \"3000.5\"\n  USDC: \"1\"\n"), 0o600)
+	assert.NoError(t, err)
+
+	oracle, err := LoadStaticOracle(path)
+	assert.NoError(t, err)
+
+	quote, err := oracle.Quote(context.Background(), "ETH")
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewRat(6001, 2), quote.Price)
+}
+
+func TestNewStaticOracleInvalidPrice(t *testing.T) {
+	_, err := NewStaticOracle(PricesConfig{Prices: map[string]string{"ETH": "lots"}})
+
+	assert.Error(t, err)
+}
+
+func TestStaticOracleUnknownSymbol(t *testing.T) {
+	oracle, _ := NewStaticOracle(PricesConfig{})
+
+	_, err := oracle.Quote(context.Background(), "ETH")
+
+	assert.ErrorIs(t, err, ErrUnknownSymbol)
+}
@@ -0,0 +1,225 @@
+package profitability
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/base-org/RIP-7755-poc/services/go-filler/bindings"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/chains"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/clients"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/pricing"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	defaultFulfillOverheadGas uint64 = 100_000
+	defaultPerCallGas         uint64 = 50_000
+	defaultClaimGas           uint64 = 500_000
+	calldataGasPerByte        uint64 = 16
+)
+
+// defaultMockClaimGas is the claim gas when proofs are mocked. A claim against
+// a mocked L1 state root carries no beacon root or execution state root
+// inclusion proof to verify.
+const defaultMockClaimGas uint64 = 300_000
+
+var gasPriceOracleAbi = mustParseAbi(`[{"type":"function","name":"getL1Fee","inputs":[{"name":"_data","type":"bytes"}],"outputs":[{"name":"","type":"uint256"}],"stateMutability":"view"}]`)
+
+// GasClient is the subset of an Ethereum client needed to price a request.
+type GasClient interface {
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+	CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+}
+
+type Engine interface {
+	Evaluate(ctx context.Context, request *bindings.CrossChainRequest) (*Estimate, error)
+}
+
+// Estimate is the cost of filling a request. Cost covers the call values,
+// destination execution (including the L1 data fee on OP Stack destinations)
+// and claiming the reward on the source chain. The destination side is priced
+// in the destination's native asset and converted into the source chain's
+// native asset, in which Cost and Required (Cost plus the route's minimum
+// margin) are expressed. On Solana, execution is the configured fulfill fee in
+// lamports, set as DestinationFee.
+type Estimate struct {
+	CallValue           *big.Int
+	DestinationGas      uint64
+	DestinationGasPrice *big.Int
+	DestinationFee      *big.Int
+	L1DataFee           *big.Int
+	ClaimGas            uint64
+	SourceGasPrice      *big.Int
+	Cost                *big.Int
+	MinMarginBps        uint64
+	Required            *big.Int
+}
+
+type engine struct {
+	srcChain *chains.ChainConfig
+	networks chains.Networks
+	oracle   pricing.PriceOracle
+	dial     func(*chains.ChainConfig) (GasClient, error)
+}
+
+// NewEngine returns an engine that reads gas prices through pool's clients.
+func NewEngine(srcChain *chains.ChainConfig, networks chains.Networks, oracle pricing.PriceOracle, pool *clients.Pool) Engine {
+	return newEngine(srcChain, networks, oracle, func(cfg *chains.ChainConfig) (GasClient, error) {
+		return pool.Eth(cfg)
+	})
+}
+
+func newEngine(srcChain *chains.ChainConfig, networks chains.Networks, oracle pricing.PriceOracle, dial func(*chains.ChainConfig) (GasClient, error)) *engine {
+	return &engine{srcChain: srcChain, networks: networks, oracle: oracle, dial: dial}
+}
+
+func (e *engine) Evaluate(ctx context.Context, request *bindings.CrossChainRequest) (*Estimate, error) {
+	dstChain, err := e.networks.GetChainConfig(request.DestinationChainId)
+	if err != nil {
+		return nil, err
+	}
+
+	srcClient, err := e.dial(e.srcChain)
+	if err != nil {
+		return nil, err
+	}
+
+	est := &Estimate{
+		CallValue:           big.NewInt(0),
+		DestinationGasPrice: big.NewInt(0),
+		DestinationFee:      big.NewInt(0),
+		ClaimGas:            claimGas(e.srcChain, dstChain),
+		L1DataFee:           big.NewInt(0),
+		MinMarginBps:        e.srcChain.Routes[request.DestinationChainId.String()].MinMarginBps,
+	}
+
+	for _, call := range request.Calls {
+		est.CallValue.Add(est.CallValue, call.Value)
+	}
+
+	if dstChain.ChainKind() == chains.KindSolana {
+		est.DestinationFee.SetUint64(dstChain.SolanaFulfillFee())
+	} else if err := e.estimateDestinationGas(ctx, est, dstChain, request); err != nil {
+		return nil, err
+	}
+
+	est.SourceGasPrice, err = srcClient.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get source gas price: %v", err)
+	}
+
+	dstCost := new(big.Int).Set(est.CallValue)
+	dstCost.Add(dstCost, new(big.Int).Mul(new(big.Int).SetUint64(est.DestinationGas), est.DestinationGasPrice))
+	dstCost.Add(dstCost, est.DestinationFee)
+	dstCost.Add(dstCost, est.L1DataFee)
+
+	srcNative := pricing.Asset{Symbol: e.srcChain.NativeAssetSymbol(), Decimals: e.srcChain.NativeAssetDecimals()}
+	dstNative := pricing.Asset{Symbol: dstChain.NativeAssetSymbol(), Decimals: dstChain.NativeAssetDecimals()}
+	est.Cost, err = pricing.Convert(ctx, e.oracle, dstCost, dstNative, srcNative)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert destination cost: %v", err)
+	}
+	est.Cost.Add(est.Cost, new(big.Int).Mul(new(big.Int).SetUint64(est.ClaimGas), est.SourceGasPrice))
+
+	est.Required = withMargin(est.Cost, est.MinMarginBps)
+
+	return est, nil
+}
+
+// estimateDestinationGas prices execution on an EVM destination.
+func (e *engine) estimateDestinationGas(ctx context.Context, est *Estimate, dstChain *chains.ChainConfig, request *bindings.CrossChainRequest) error {
+	dstClient, err := e.dial(dstChain)
+	if err != nil {
+		return err
+	}
+
+	est.DestinationGas = destinationGas(dstChain, request)
+	est.DestinationGasPrice, err = dstClient.SuggestGasPrice(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get destination gas price: %v", err)
+	}
+
+	if dstChain.Contracts != nil && dstChain.Contracts.GasPriceOracle != (common.Address{}) {
+		est.L1DataFee, err = l1DataFee(ctx, dstClient, dstChain.Contracts.GasPriceOracle, request)
+		if err != nil {
+			return fmt.Errorf("failed to get L1 data fee: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func destinationGas(dstChain *chains.ChainConfig, request *bindings.CrossChainRequest) uint64 {
+	gas := valueOrDefault(dstChain.Gas.FulfillOverhead, defaultFulfillOverheadGas)
+	perCall := valueOrDefault(dstChain.Gas.PerCall, defaultPerCallGas)
+
+	for _, call := range request.Calls {
+		gas += perCall + calldataGasPerByte*uint64(len(call.Data))
+	}
+
+	return gas
+}
+
+func claimGas(srcChain, dstChain *chains.ChainConfig) uint64 {
+	fallback := defaultClaimGas
+	if srcChain.MocksProofs(dstChain) {
+		fallback = defaultMockClaimGas
+	}
+	return valueOrDefault(srcChain.Gas.Claim[string(srcChain.SelectProver(dstChain))], fallback)
+}
+
+// l1DataFee asks the OP Stack GasPriceOracle predeploy what it would charge to
+// post the fulfillment calldata to L1. The encoded request is used as a proxy
+// for the fulfillment calldata.
+func l1DataFee(ctx context.Context, client GasClient, oracle common.Address, request *bindings.CrossChainRequest) (*big.Int, error) {
+	outboxAbi, err := bindings.RIP7755OutboxMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+
+	txData, err := outboxAbi.Pack("requestCrossChainCall", *request)
+	if err != nil {
+		return nil, err
+	}
+
+	input, err := gasPriceOracleAbi.Pack("getL1Fee", txData)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.CallContract(ctx, ethereum.CallMsg{To: &oracle, Data: input}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := gasPriceOracleAbi.Unpack("getL1Fee", res)
+	if err != nil {
+		return nil, err
+	}
+
+	return out[0].(*big.Int), nil
+}
+
+func withMargin(cost *big.Int, marginBps uint64) *big.Int {
+	required := new(big.Int).Mul(cost, new(big.Int).SetUint64(10_000+marginBps))
+	return required.Div(required, big.NewInt(10_000))
+}
+
+func valueOrDefault(v, d uint64) uint64 {
+	if v == 0 {
+		return d
+	}
+	return v
+}
+
+func mustParseAbi(def string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(def))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
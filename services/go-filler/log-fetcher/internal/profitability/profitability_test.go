@@ -0,0 +1,207 @@
+package profitability
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/base-org/RIP-7755-poc/services/go-filler/bindings"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/chains"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/pricing"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/provers"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type GasClientMock struct {
+	mock.Mock
+}
+
+func (c *GasClientMock) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	args := c.Called(ctx)
+	return args.Get(0).(*big.Int), args.Error(1)
+}
+
+func (c *GasClientMock) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	args := c.Called(ctx, msg, blockNumber)
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+var srcChain = &chains.ChainConfig{
+	ChainId: big.NewInt(421614),
+	Gas: chains.GasConfig{
+		Claim: map[string]uint64{"OPStack": 400_000},
+	},
+	Routes: map[string]chains.RouteConfig{
+		"84532": {MinMarginBps: 1_000},
+	},
+}
+
+var networks = chains.Networks{
+	"421614": *srcChain,
+	"84532": {
+		ChainId:      big.NewInt(84532),
+		Contracts:    &chains.Contracts{GasPriceOracle: common.HexToAddress("0x420000000000000000000000000000000000000F")},
+		TargetProver: provers.OPStackProver,
+		Gas:          chains.GasConfig{FulfillOverhead: 80_000, PerCall: 20_000},
+	},
+}
+
+var request = &bindings.CrossChainRequest{
+	Calls: []bindings.Call{
+		{To: common.HexToAddress("0x1"), Data: []byte{1, 2}, Value: big.NewInt(1_000)},
+	},
+	DestinationChainId:   big.NewInt(84532),
+	RewardAmount:         big.NewInt(0),
+	FinalityDelaySeconds: big.NewInt(0),
+	Nonce:                big.NewInt(0),
+	Expiry:               big.NewInt(0),
+}
+
+var oracle, _ = pricing.NewStaticOracle(pricing.PricesConfig{Prices: map[string]string{"ETH": "2000", "POL": "0.5", "SOL": "100"}})
+
+func newTestEngine(src, dst GasClient) *engine {
+	return newEngine(srcChain, networks, oracle, func(cfg *chains.ChainConfig) (GasClient, error) {
+		if cfg.ChainId.Cmp(srcChain.ChainId) == 0 {
+			return src, nil
+		}
+		return dst, nil
+	})
+}
+
+func TestEvaluate(t *testing.T) {
+	src := new(GasClientMock)
+	dst := new(GasClientMock)
+
+	l1Fee := common.LeftPadBytes(big.NewInt(5_000).Bytes(), 32)
+	dst.On("SuggestGasPrice", mock.Anything).Return(big.NewInt(2), nil)
+	dst.On("CallContract", mock.Anything, mock.Anything, mock.Anything).Return(l1Fee, nil)
+	src.On("SuggestGasPrice", mock.Anything).Return(big.NewInt(3), nil)
+
+	est, err := newTestEngine(src, dst).Evaluate(context.Background(), request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(80_000+20_000+2*16), est.DestinationGas)
+	assert.Equal(t, uint64(400_000), est.ClaimGas)
+	assert.Equal(t, big.NewInt(5_000), est.L1DataFee)
+
+	// 1_000 value + 100_032 * 2 dst gas + 5_000 l1 fee + 400_000 * 3 claim gas
+	assert.Equal(t, big.NewInt(1_406_064), est.Cost)
+	assert.Equal(t, big.NewInt(1_546_670), est.Required)
+}
+
+func TestEvaluateUsesDefaultsWithoutGasConfig(t *testing.T) {
+	src := new(GasClientMock)
+	dst := new(GasClientMock)
+
+	src.On("SuggestGasPrice", mock.Anything).Return(big.NewInt(1), nil)
+	dst.On("SuggestGasPrice", mock.Anything).Return(big.NewInt(1), nil)
+
+	e := newEngine(&chains.ChainConfig{ChainId: big.NewInt(1)}, chains.Networks{"84532": {ChainId: big.NewInt(84532)}}, oracle, func(cfg *chains.ChainConfig) (GasClient, error) {
+		if cfg.ChainId.Int64() == 1 {
+			return src, nil
+		}
+		return dst, nil
+	})
+
+	est, err := e.Evaluate(context.Background(), request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, defaultFulfillOverheadGas+defaultPerCallGas+2*calldataGasPerByte, est.DestinationGas)
+	assert.Equal(t, defaultClaimGas, est.ClaimGas)
+	assert.Equal(t, big.NewInt(0), est.L1DataFee)
+	assert.Equal(t, est.Cost, est.Required)
+	dst.AssertNotCalled(t, "CallContract", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestClaimGasWithMockedProofs(t *testing.T) {
+	no := false
+	devnet := &chains.ChainConfig{MockProofs: true}
+	dst := &chains.ChainConfig{TargetProver: provers.OPStackProver}
+
+	assert.Equal(t, defaultMockClaimGas, claimGas(devnet, dst))
+	assert.Equal(t, defaultClaimGas, claimGas(devnet, &chains.ChainConfig{TargetProver: provers.OPStackProver, SharesStateWithL1: &no}))
+
+	devnet.Gas.Claim = map[string]uint64{string(provers.OPStackProver): 250_000}
+	assert.Equal(t, uint64(250_000), claimGas(devnet, dst))
+}
+
+func TestEvaluateConvertsDestinationNativeAsset(t *testing.T) {
+	src := new(GasClientMock)
+	dst := new(GasClientMock)
+
+	src.On("SuggestGasPrice", mock.Anything).Return(big.NewInt(0), nil)
+	dst.On("SuggestGasPrice", mock.Anything).Return(big.NewInt(0), nil)
+
+	dstChain := chains.ChainConfig{ChainId: big.NewInt(84532), NativeSymbol: "POL"}
+	e := newEngine(&chains.ChainConfig{ChainId: big.NewInt(1)}, chains.Networks{"84532": dstChain}, oracle, func(cfg *chains.ChainConfig) (GasClient, error) {
+		if cfg.ChainId.Int64() == 1 {
+			return src, nil
+		}
+		return dst, nil
+	})
+
+	polRequest := *request
+	polRequest.Calls = []bindings.Call{{To: common.HexToAddress("0x1"), Value: big.NewInt(4_000_000)}}
+
+	est, err := e.Evaluate(context.Background(), &polRequest)
+
+	// 4_000_000 POL-wei at 0.5 USD is worth 1_000 ETH-wei at 2000 USD
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(4_000_000), est.CallValue)
+	assert.Equal(t, 0, est.Cost.Cmp(big.NewInt(1_000)))
+}
+
+func TestEvaluateSolanaDestination(t *testing.T) {
+	src := new(GasClientMock)
+	src.On("SuggestGasPrice", mock.Anything).Return(big.NewInt(0), nil)
+
+	dstChain := chains.ChainConfig{Kind: chains.KindSolana, ChainId: big.NewInt(103), Solana: &chains.SolanaConfig{FulfillFee: 1_000_000}}
+	e := newEngine(&chains.ChainConfig{ChainId: big.NewInt(1)}, chains.Networks{"103": dstChain}, oracle, func(cfg *chains.ChainConfig) (GasClient, error) {
+		if cfg.ChainId.Int64() == 1 {
+			return src, nil
+		}
+		t.Fatal("dialed the Solana chain as an EVM chain")
+		return nil, nil
+	})
+
+	solRequest := *request
+	solRequest.DestinationChainId = big.NewInt(103)
+	solRequest.Calls = []bindings.Call{{Value: big.NewInt(1_000_000_000)}}
+
+	est, err := e.Evaluate(context.Background(), &solRequest)
+
+	// 1.001 SOL at 100 USD is worth 0.05005 ETH at 2000 USD
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), est.DestinationGas)
+	assert.Equal(t, big.NewInt(1_000_000), est.DestinationFee)
+	assert.Equal(t, 0, est.Cost.Cmp(big.NewInt(50_050_000_000_000_000)))
+}
+
+func TestEvaluateReturnsGasPriceError(t *testing.T) {
+	src := new(GasClientMock)
+	dst := new(GasClientMock)
+
+	dst.On("SuggestGasPrice", mock.Anything).Return((*big.Int)(nil), errors.New("error"))
+
+	_, err := newTestEngine(src, dst).Evaluate(context.Background(), request)
+
+	assert.Error(t, err)
+}
+
+func TestEvaluateUnknownDestinationChain(t *testing.T) {
+	unknown := *request
+	unknown.DestinationChainId = big.NewInt(1)
+
+	_, err := newTestEngine(new(GasClientMock), new(GasClientMock)).Evaluate(context.Background(), &unknown)
+
+	assert.Error(t, err)
+}
+
+func TestWithMargin(t *testing.T) {
+	assert.Equal(t, big.NewInt(100), withMargin(big.NewInt(100), 0))
+	assert.Equal(t, big.NewInt(125), withMargin(big.NewInt(100), 2_500))
+}
@@ -6,4 +6,39 @@ const (
 	NilProver      Prover = "None"
 	ArbitrumProver Prover = "Arbitrum"
 	OPStackProver  Prover = "OPStack"
+	HashiProver    Prover = "Hashi"
 )
+
+// Latency estimates, in seconds, how long each step of filling a request takes
+// when its destination is proven with a given prover. Fulfill is the time to
+// land the fulfillment on the destination chain, Proof the time until the
+// destination state containing it can be proven on the source chain, and Claim
+// the time to land the claim on the source chain.
+type Latency struct {
+	FulfillSeconds uint64 `yaml:"fulfill-seconds"`
+	ProofSeconds   uint64 `yaml:"proof-seconds"`
+	ClaimSeconds   uint64 `yaml:"claim-seconds"`
+}
+
+// DefaultLatency holds conservative estimates for each prover. OP Stack and
+// Arbitrum proofs go through L1 and wait on the dispute game and the assertion
+// being confirmed respectively, Hashi waits on its oracles' reports.
+var DefaultLatency = map[Prover]Latency{
+	OPStackProver:  {FulfillSeconds: 300, ProofSeconds: 4 * 24 * 60 * 60, ClaimSeconds: 300},
+	ArbitrumProver: {FulfillSeconds: 300, ProofSeconds: 7 * 24 * 60 * 60, ClaimSeconds: 300},
+	HashiProver:    {FulfillSeconds: 300, ProofSeconds: 60 * 60, ClaimSeconds: 300},
+}
+
+// Merge returns l with unset fields filled from d.
+func (l Latency) Merge(d Latency) Latency {
+	if l.FulfillSeconds == 0 {
+		l.FulfillSeconds = d.FulfillSeconds
+	}
+	if l.ProofSeconds == 0 {
+		l.ProofSeconds = d.ProofSeconds
+	}
+	if l.ClaimSeconds == 0 {
+		l.ClaimSeconds = d.ClaimSeconds
+	}
+	return l
+}
@@ -0,0 +1,38 @@
+package requesters
+
+import "github.com/ethereum/go-ethereum/common"
+
+type Verdict int
+
+const (
+	Accepted Verdict = iota
+	Blocked
+	Throttled
+)
+
+// Guard decides whether a requester's request should be looked at at all. A
+// nil Guard accepts everyone.
+type Guard struct {
+	lists   *Lists
+	limiter *Limiter
+}
+
+func NewGuard(lists *Lists, limiter *Limiter) *Guard {
+	if lists == nil {
+		lists = NewLists(ListsConfig{})
+	}
+	return &Guard{lists: lists, limiter: limiter}
+}
+
+func (g *Guard) Check(requester common.Address, requestHash [32]byte) Verdict {
+	if g == nil {
+		return Accepted
+	}
+	if g.lists.Blocked(requester) {
+		return Blocked
+	}
+	if g.lists.Allowed(requester) || g.limiter == nil || g.limiter.Allow(requester, requestHash) {
+		return Accepted
+	}
+	return Throttled
+}
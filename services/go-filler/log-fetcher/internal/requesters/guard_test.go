@@ -0,0 +1,29 @@
+package requesters
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGuard(t *testing.T) {
+	lists := NewLists(ListsConfig{Blocklist: []common.Address{blocked}, Allowlist: []common.Address{requester}})
+	guard := NewGuard(lists, NewLimiter([]Limit{{Max: 1, Window: time.Minute}}))
+	other := common.HexToAddress("0x3333333333333333333333333333333333333333")
+
+	assert.Equal(t, Blocked, guard.Check(blocked, newHash()))
+	assert.Equal(t, Accepted, guard.Check(other, newHash()))
+	assert.Equal(t, Throttled, guard.Check(other, newHash()))
+
+	// allowlisted requesters are never throttled
+	assert.Equal(t, Accepted, guard.Check(requester, newHash()))
+	assert.Equal(t, Accepted, guard.Check(requester, newHash()))
+}
+
+func TestNilGuardAcceptsEveryone(t *testing.T) {
+	var guard *Guard
+
+	assert.Equal(t, Accepted, guard.Check(blocked, newHash()))
+}
@@ -0,0 +1,113 @@
+package requesters
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Limit allows at most Max requests per requester in any Window.
+type Limit struct {
+	Max    int
+	Window time.Duration
+}
+
+// ParseLimit parses a limit written as max/window, e.g. 20/1m.
+func ParseLimit(s string) (Limit, error) {
+	max, window, ok := strings.Cut(s, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("invalid rate limit %q, want max/window", s)
+	}
+
+	n, err := strconv.Atoi(max)
+	if err != nil || n <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: max must be a positive integer", s)
+	}
+
+	d, err := time.ParseDuration(window)
+	if err != nil || d <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: window must be a positive duration", s)
+	}
+
+	return Limit{Max: n, Window: d}, nil
+}
+
+// Limiter throttles requesters over sliding windows. It keeps each request it
+// let through within the longest window, so that a request seen again, e.g.
+// when logs are polled again, isn't counted twice.
+type Limiter struct {
+	limits    []Limit
+	maxWindow time.Duration
+	now       func() time.Time
+
+	mu   sync.Mutex
+	seen map[common.Address][]seenRequest
+}
+
+type seenRequest struct {
+	hash [32]byte
+	at   time.Time
+}
+
+func NewLimiter(limits []Limit) *Limiter {
+	l := &Limiter{limits: limits, now: time.Now, seen: make(map[common.Address][]seenRequest)}
+	for _, limit := range limits {
+		if limit.Window > l.maxWindow {
+			l.maxWindow = limit.Window
+		}
+	}
+	return l
+}
+
+// Allow reports whether requester is within all limits, and if so counts the
+// request against them. Requests already let through are always allowed.
+func (l *Limiter) Allow(requester common.Address, requestHash [32]byte) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	seen := prune(l.seen[requester], now.Add(-l.maxWindow))
+
+	for _, r := range seen {
+		if r.hash == requestHash {
+			l.store(requester, seen)
+			return true
+		}
+	}
+
+	for _, limit := range l.limits {
+		if countSince(seen, now.Add(-limit.Window)) >= limit.Max {
+			l.store(requester, seen)
+			return false
+		}
+	}
+
+	l.store(requester, append(seen, seenRequest{hash: requestHash, at: now}))
+	return true
+}
+
+func (l *Limiter) store(requester common.Address, seen []seenRequest) {
+	if len(seen) == 0 {
+		delete(l.seen, requester)
+		return
+	}
+	l.seen[requester] = seen
+}
+
+// prune drops the requests before cutoff from seen, which is in ascending
+// order.
+func prune(seen []seenRequest, cutoff time.Time) []seenRequest {
+	i := 0
+	for i < len(seen) && !seen[i].at.After(cutoff) {
+		i++
+	}
+	return seen[i:]
+}
+
+func countSince(seen []seenRequest, cutoff time.Time) int {
+	return len(prune(seen, cutoff))
+}
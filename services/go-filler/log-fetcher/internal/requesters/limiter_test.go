@@ -0,0 +1,89 @@
+package requesters
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+var requester = common.HexToAddress("0x1111111111111111111111111111111111111111")
+
+var nextHash byte
+
+// newHash returns a request hash that hasn't been used yet.
+func newHash() [32]byte {
+	nextHash++
+	return [32]byte{nextHash}
+}
+
+func TestParseLimit(t *testing.T) {
+	limit, err := ParseLimit("20/1m")
+
+	assert.NoError(t, err)
+	assert.Equal(t, Limit{Max: 20, Window: time.Minute}, limit)
+
+	for _, s := range []string{"20", "0/1m", "x/1m", "20/0s", "20/soon"} {
+		_, err := ParseLimit(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestLimiterSlidingWindow(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	limiter := NewLimiter([]Limit{{Max: 2, Window: time.Minute}})
+	limiter.now = func() time.Time { return now }
+
+	assert.True(t, limiter.Allow(requester, newHash()))
+	now = now.Add(30 * time.Second)
+	assert.True(t, limiter.Allow(requester, newHash()))
+	assert.False(t, limiter.Allow(requester, newHash()))
+
+	// the first request leaves the window
+	now = now.Add(31 * time.Second)
+	assert.True(t, limiter.Allow(requester, newHash()))
+	assert.False(t, limiter.Allow(requester, newHash()))
+}
+
+func TestLimiterAppliesEveryWindow(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	limiter := NewLimiter([]Limit{{Max: 2, Window: time.Minute}, {Max: 3, Window: time.Hour}})
+	limiter.now = func() time.Time { return now }
+
+	assert.True(t, limiter.Allow(requester, newHash()))
+	assert.True(t, limiter.Allow(requester, newHash()))
+	now = now.Add(2 * time.Minute)
+	assert.True(t, limiter.Allow(requester, newHash()))
+	assert.False(t, limiter.Allow(requester, newHash()))
+}
+
+func TestLimiterCountsRequestsOnce(t *testing.T) {
+	limiter := NewLimiter([]Limit{{Max: 1, Window: time.Minute}})
+	hash := newHash()
+
+	assert.True(t, limiter.Allow(requester, hash))
+	assert.True(t, limiter.Allow(requester, hash))
+	assert.False(t, limiter.Allow(requester, newHash()))
+}
+
+func TestLimiterIsPerRequester(t *testing.T) {
+	limiter := NewLimiter([]Limit{{Max: 1, Window: time.Minute}})
+
+	assert.True(t, limiter.Allow(requester, newHash()))
+	assert.False(t, limiter.Allow(requester, newHash()))
+	assert.True(t, limiter.Allow(common.HexToAddress("0x2222222222222222222222222222222222222222"), newHash()))
+}
+
+func TestLimiterForgetsIdleRequesters(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	limiter := NewLimiter([]Limit{{Max: 1, Window: time.Minute}})
+	limiter.now = func() time.Time { return now }
+
+	limiter.Allow(requester, newHash())
+	now = now.Add(2 * time.Minute)
+	limiter.limits = []Limit{{Max: 0, Window: time.Minute}}
+	limiter.Allow(requester, newHash())
+
+	assert.Empty(t, limiter.seen)
+}
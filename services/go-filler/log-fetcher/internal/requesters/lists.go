@@ -0,0 +1,106 @@
+package requesters
+
+import (
+	"os"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gopkg.in/yaml.v2"
+)
+
+// ListsConfig is the requester lists file, e.g.
+//
+//	blocklist:
+//	  - 0x...
+//	allowlist:
+//	  - 0x...
+//
+// Blocked requesters are always rejected, allowed requesters are never
+// throttled.
+type ListsConfig struct {
+	Blocklist []common.Address `yaml:"blocklist"`
+	Allowlist []common.Address `yaml:"allowlist"`
+}
+
+type Lists struct {
+	path string
+
+	mu      sync.RWMutex
+	blocked map[common.Address]bool
+	allowed map[common.Address]bool
+}
+
+func NewLists(cfg ListsConfig) *Lists {
+	l := &Lists{}
+	l.set(cfg)
+	return l
+}
+
+// LoadLists reads the lists from path. Call Reload to pick up changes to the
+// file.
+func LoadLists(path string) (*Lists, error) {
+	cfg, err := readLists(path)
+	if err != nil {
+		return nil, err
+	}
+
+	l := NewLists(cfg)
+	l.path = path
+	return l, nil
+}
+
+func (l *Lists) Blocked(requester common.Address) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.blocked[requester]
+}
+
+func (l *Lists) Allowed(requester common.Address) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.allowed[requester]
+}
+
+// Path returns the file the lists were loaded from.
+func (l *Lists) Path() string {
+	return l.path
+}
+
+// Reload re-reads the lists from their file. If the file can't be read or
+// parsed the previous lists stay in place.
+func (l *Lists) Reload() error {
+	cfg, err := readLists(l.path)
+	if err != nil {
+		return err
+	}
+
+	l.set(cfg)
+	return nil
+}
+
+func (l *Lists) set(cfg ListsConfig) {
+	blocked := make(map[common.Address]bool, len(cfg.Blocklist))
+	for _, addr := range cfg.Blocklist {
+		blocked[addr] = true
+	}
+	allowed := make(map[common.Address]bool, len(cfg.Allowlist))
+	for _, addr := range cfg.Allowlist {
+		allowed[addr] = true
+	}
+
+	l.mu.Lock()
+	l.blocked, l.allowed = blocked, allowed
+	l.mu.Unlock()
+}
+
+func readLists(path string) (ListsConfig, error) {
+	var cfg ListsConfig
+
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+
+	err = yaml.Unmarshal(file, &cfg)
+	return cfg, err
+}
@@ -0,0 +1,48 @@
+package requesters
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+var blocked = common.HexToAddress("0x2222222222222222222222222222222222222222")
+
+func TestLoadLists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "requesters.yaml")
+	os.WriteFile(path, []byte("blocklist: ["+blocked.Hex()+"]\nallowlist: ["+requester.Hex()+"]\n"), 0o644)
+
+	lists, err := LoadLists(path)
+
+	assert.NoError(t, err)
+	assert.True(t, lists.Blocked(blocked))
+	assert.False(t, lists.Allowed(blocked))
+	assert.True(t, lists.Allowed(requester))
+}
+
+func TestLoadListsMissingFile(t *testing.T) {
+	_, err := LoadLists(filepath.Join(t.TempDir(), "missing.yaml"))
+
+	assert.Error(t, err)
+}
+
+func TestListsReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "requesters.yaml")
+	os.WriteFile(path, []byte("blocklist: []\n"), 0o644)
+
+	lists, err := LoadLists(path)
+	assert.NoError(t, err)
+	assert.Equal(t, path, lists.Path())
+
+	os.WriteFile(path, []byte("blocklist: ["+blocked.Hex()+"]\n"), 0o644)
+	assert.NoError(t, lists.Reload())
+	assert.True(t, lists.Blocked(blocked))
+
+	// a broken file keeps the previous lists
+	os.WriteFile(path, []byte("blocklist: [not an address]\n"), 0o644)
+	assert.Error(t, lists.Reload())
+	assert.True(t, lists.Blocked(blocked))
+}
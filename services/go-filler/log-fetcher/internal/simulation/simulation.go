@@ -0,0 +1,129 @@
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/base-org/RIP-7755-poc/services/go-filler/bindings"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/chains"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/clients"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient/gethclient"
+	logger "github.com/ethereum/go-ethereum/log"
+)
+
+// fulfill is RIP7755Inbox.fulfill(CrossChainRequest, address).
+var fulfill = mustFulfillMethod()
+
+// RPCClient is the raw JSON-RPC client used to simulate, state overrides aren't
+// exposed by ethclient.
+type RPCClient interface {
+	CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error
+}
+
+// Result is the outcome of simulating a fulfillment. GasUsed is zero if the
+// node couldn't estimate gas for a successful call.
+type Result struct {
+	Success      bool
+	ReturnData   []byte
+	GasUsed      uint64
+	RevertReason string
+}
+
+type Simulator interface {
+	SimulateFulfill(ctx context.Context, request *bindings.CrossChainRequest, dstChain *chains.ChainConfig) (*Result, error)
+}
+
+type simulator struct {
+	dial func(*chains.ChainConfig) (RPCClient, error)
+}
+
+// NewSimulator returns a simulator that calls nodes through pool's clients.
+func NewSimulator(pool *clients.Pool) Simulator {
+	return newSimulator(func(cfg *chains.ChainConfig) (RPCClient, error) {
+		client, err := pool.Eth(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return client.RPC(), nil
+	})
+}
+
+func newSimulator(dial func(*chains.ChainConfig) (RPCClient, error)) *simulator {
+	return &simulator{dial: dial}
+}
+
+// SimulateFulfill runs the Inbox fulfill call for request with eth_call on the
+// latest destination state, from the destination chain's fulfiller. The
+// fulfiller's balance is overridden to cover the call values so the simulation
+// doesn't depend on our current funding. A revert is reported in the Result,
+// only failures to simulate at all are returned as errors.
+func (s *simulator) SimulateFulfill(ctx context.Context, request *bindings.CrossChainRequest, dstChain *chains.ChainConfig) (*Result, error) {
+	if dstChain.Fulfiller == (common.Address{}) {
+		return nil, fmt.Errorf("no fulfiller configured for destination chain %s", dstChain.ChainId)
+	}
+
+	input, err := fulfill.Inputs.Pack(*request, dstChain.Fulfiller)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode fulfill call: %v", err)
+	}
+
+	value := big.NewInt(0)
+	for _, call := range request.Calls {
+		value.Add(value, call.Value)
+	}
+
+	client, err := s.dial(dstChain)
+	if err != nil {
+		return nil, err
+	}
+
+	args := map[string]interface{}{
+		"from":  dstChain.Fulfiller,
+		"to":    dstChain.Contracts.Inbox,
+		"value": (*hexutil.Big)(value),
+		"input": hexutil.Bytes(append(fulfill.ID, input...)),
+	}
+	overrides := map[common.Address]gethclient.OverrideAccount{
+		dstChain.Fulfiller: {Balance: value},
+	}
+
+	var returnData hexutil.Bytes
+	err = client.CallContext(ctx, &returnData, "eth_call", args, "latest", overrides)
+	if reason, reverted := clients.RevertReason(err); reverted {
+		return &Result{RevertReason: reason}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to simulate fulfill: %v", err)
+	}
+
+	result := &Result{Success: true, ReturnData: returnData}
+
+	var gas hexutil.Uint64
+	if err := client.CallContext(ctx, &gas, "eth_estimateGas", args, "latest", overrides); err != nil {
+		logger.Warn("Failed to estimate fulfill gas", "chainId", dstChain.ChainId, "error", err)
+	} else {
+		result.GasUsed = uint64(gas)
+	}
+
+	return result, nil
+}
+
+func mustFulfillMethod() abi.Method {
+	outboxAbi, err := bindings.RIP7755OutboxMetaData.GetAbi()
+	if err != nil {
+		panic(err)
+	}
+
+	requestType := outboxAbi.Methods["requestCrossChainCall"].Inputs[0].Type
+	addressType, err := abi.NewType("address", "", nil)
+	if err != nil {
+		panic(err)
+	}
+
+	inputs := abi.Arguments{{Name: "request", Type: requestType}, {Name: "fulfiller", Type: addressType}}
+	return abi.NewMethod("fulfill", "fulfill", abi.Function, "payable", false, true, inputs, nil)
+}
@@ -0,0 +1,128 @@
+package simulation
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/base-org/RIP-7755-poc/services/go-filler/bindings"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/chains"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient/gethclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type RPCClientMock struct {
+	mock.Mock
+}
+
+func (c *RPCClientMock) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	called := c.MethodCalled(method, args[0], args[2])
+	if fn, ok := called.Get(0).(func(interface{})); ok {
+		fn(result)
+	}
+	return called.Error(1)
+}
+
+type revertError struct{}
+
+func (e *revertError) Error() string  { return "execution reverted" }
+func (e *revertError) ErrorCode() int { return 3 }
+
+var fulfiller = common.HexToAddress("0x4444444444444444444444444444444444444444")
+
+var dstChain = &chains.ChainConfig{
+	ChainId:   big.NewInt(84532),
+	Fulfiller: fulfiller,
+	Contracts: &chains.Contracts{
+		Inbox: common.HexToAddress("0xB482b292878FDe64691d028A2237B34e91c7c7ea"),
+	},
+}
+
+var request = &bindings.CrossChainRequest{
+	Calls: []bindings.Call{
+		{To: common.HexToAddress("0x1234567890123456789012345678901234567890"), Value: big.NewInt(1000)},
+		{To: common.HexToAddress("0x1234567890123456789012345678901234567890"), Value: big.NewInt(500)},
+	},
+	DestinationChainId:   big.NewInt(84532),
+	RewardAmount:         big.NewInt(2000),
+	FinalityDelaySeconds: big.NewInt(0),
+	Nonce:                big.NewInt(1),
+	Expiry:               big.NewInt(1700000000),
+}
+
+func newTestSimulator(client *RPCClientMock) *simulator {
+	return newSimulator(func(*chains.ChainConfig) (RPCClient, error) {
+		return client, nil
+	})
+}
+
+func fundsCallValue(overrides interface{}) bool {
+	o, ok := overrides.(map[common.Address]gethclient.OverrideAccount)
+	return ok && o[fulfiller].Balance.Cmp(big.NewInt(1500)) == 0
+}
+
+func TestSimulateFulfill(t *testing.T) {
+	client := new(RPCClientMock)
+	client.On("eth_call", mock.MatchedBy(func(args map[string]interface{}) bool {
+		return args["from"] == fulfiller && args["to"] == dstChain.Contracts.Inbox && (*big.Int)(args["value"].(*hexutil.Big)).Cmp(big.NewInt(1500)) == 0
+	}), mock.MatchedBy(fundsCallValue)).Return(func(result interface{}) {
+		*result.(*hexutil.Bytes) = []byte{0x01}
+	}, nil)
+	client.On("eth_estimateGas", mock.Anything, mock.MatchedBy(fundsCallValue)).Return(func(result interface{}) {
+		*result.(*hexutil.Uint64) = 210000
+	}, nil)
+
+	result, err := newTestSimulator(client).SimulateFulfill(context.Background(), request, dstChain)
+
+	assert.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Equal(t, []byte{0x01}, result.ReturnData)
+	assert.Equal(t, uint64(210000), result.GasUsed)
+	client.AssertExpectations(t)
+}
+
+func TestSimulateFulfill_Reverts(t *testing.T) {
+	client := new(RPCClientMock)
+	client.On("eth_call", mock.Anything, mock.Anything).Return(nil, &revertError{})
+
+	result, err := newTestSimulator(client).SimulateFulfill(context.Background(), request, dstChain)
+
+	assert.NoError(t, err)
+	assert.False(t, result.Success)
+	assert.Equal(t, "execution reverted", result.RevertReason)
+	client.AssertNotCalled(t, "eth_estimateGas", mock.Anything, mock.Anything)
+}
+
+func TestSimulateFulfill_RpcError(t *testing.T) {
+	client := new(RPCClientMock)
+	client.On("eth_call", mock.Anything, mock.Anything).Return(nil, errors.New("connection refused"))
+
+	_, err := newTestSimulator(client).SimulateFulfill(context.Background(), request, dstChain)
+
+	assert.Error(t, err)
+}
+
+func TestSimulateFulfill_EstimateGasUnsupported(t *testing.T) {
+	client := new(RPCClientMock)
+	client.On("eth_call", mock.Anything, mock.Anything).Return(nil, nil)
+	client.On("eth_estimateGas", mock.Anything, mock.Anything).Return(nil, errors.New("too many arguments"))
+
+	result, err := newTestSimulator(client).SimulateFulfill(context.Background(), request, dstChain)
+
+	assert.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Zero(t, result.GasUsed)
+}
+
+func TestSimulateFulfill_NoFulfiller(t *testing.T) {
+	noFulfiller := *dstChain
+	noFulfiller.Fulfiller = common.Address{}
+
+	_, err := newTestSimulator(new(RPCClientMock)).SimulateFulfill(context.Background(), request, &noFulfiller)
+
+	assert.Error(t, err)
+}
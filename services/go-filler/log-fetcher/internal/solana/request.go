@@ -0,0 +1,154 @@
+// Package solana describes requests bound for the RRC-7755 inbox program in
+// contracts/solana in the form the program takes them.
+package solana
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"github.com/base-org/RIP-7755-poc/services/go-filler/bindings"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/ids"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Call mirrors the inbox program's Call. Calls without data transfer Value
+// lamports to To, other calls invoke the program To.
+type Call struct {
+	To    ids.Pubkey
+	Data  []byte
+	Value uint64
+}
+
+// Request mirrors the inbox program's CrossChainRequest, field for field, so
+// that its Borsh encoding is what the program expects.
+type Request struct {
+	Requester            ids.Pubkey
+	Calls                []Call
+	SourceChainId        uint64
+	Origin               ids.Pubkey
+	DestinationChainId   uint64
+	InboxContract        ids.Pubkey
+	L2Oracle             ids.Pubkey
+	L2OracleStorageKey   [32]byte
+	RewardAsset          ids.Pubkey
+	RewardAmount         uint64
+	FinalityDelaySeconds uint64
+	Nonce                uint64
+	Expiry               uint64
+	ExtraData            [][]byte
+}
+
+// FromLog converts a request emitted on the EVM chain srcChainId. Accounts are
+// carried over as bytes32, as in GlobalTypes.addressToBytes32, and the origin
+// is the Outbox that emitted the request. A precheck contract goes first in
+// ExtraData, where the program looks for it. The program's amounts, ids and
+// timestamps are u64, so it fails if any of them is larger.
+func FromLog(log *bindings.RIP7755OutboxCrossChainCallRequested, srcChainId *big.Int) (*Request, error) {
+	request := &log.Request
+
+	r := &Request{
+		Requester:          ids.Pubkey(ids.AddressToBytes32(request.Requester)),
+		Origin:             ids.Pubkey(ids.AddressToBytes32(log.Raw.Address)),
+		InboxContract:      ids.Pubkey(ids.AddressToBytes32(request.InboxContract)),
+		L2Oracle:           ids.Pubkey(ids.AddressToBytes32(request.L2Oracle)),
+		L2OracleStorageKey: request.L2OracleStorageKey,
+		RewardAsset:        ids.Pubkey(ids.AddressToBytes32(request.RewardAsset)),
+	}
+
+	u64s := []struct {
+		name  string
+		value *big.Int
+		into  *uint64
+	}{
+		{"source chain id", srcChainId, &r.SourceChainId},
+		{"destination chain id", request.DestinationChainId, &r.DestinationChainId},
+		{"reward amount", request.RewardAmount, &r.RewardAmount},
+		{"finality delay", request.FinalityDelaySeconds, &r.FinalityDelaySeconds},
+		{"nonce", request.Nonce, &r.Nonce},
+		{"expiry", request.Expiry, &r.Expiry},
+	}
+	for _, f := range u64s {
+		v, err := toUint64(f.value)
+		if err != nil {
+			return nil, fmt.Errorf("%s %v", f.name, err)
+		}
+		*f.into = v
+	}
+
+	for i, call := range request.Calls {
+		value, err := toUint64(call.Value)
+		if err != nil {
+			return nil, fmt.Errorf("call %d value %v", i, err)
+		}
+		r.Calls = append(r.Calls, Call{To: ids.Pubkey(ids.AddressToBytes32(call.To)), Data: call.Data, Value: value})
+	}
+
+	if request.PrecheckContract != (common.Address{}) {
+		precheck := ids.AddressToBytes32(request.PrecheckContract)
+		r.ExtraData = [][]byte{append(precheck[:], request.PrecheckData...)}
+	}
+
+	return r, nil
+}
+
+// TotalValue returns the lamports the calls transfer.
+func (r *Request) TotalValue() *big.Int {
+	total := new(big.Int)
+	for _, call := range r.Calls {
+		total.Add(total, new(big.Int).SetUint64(call.Value))
+	}
+	return total
+}
+
+// Borsh returns the request's Borsh encoding, which is how it is passed to
+// the program's fulfill instruction.
+func (r *Request) Borsh() []byte {
+	var b []byte
+	b = append(b, r.Requester[:]...)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(r.Calls)))
+	for _, call := range r.Calls {
+		b = append(b, call.To[:]...)
+		b = appendBytes(b, call.Data)
+		b = binary.LittleEndian.AppendUint64(b, call.Value)
+	}
+	b = binary.LittleEndian.AppendUint64(b, r.SourceChainId)
+	b = append(b, r.Origin[:]...)
+	b = binary.LittleEndian.AppendUint64(b, r.DestinationChainId)
+	b = append(b, r.InboxContract[:]...)
+	b = append(b, r.L2Oracle[:]...)
+	b = append(b, r.L2OracleStorageKey[:]...)
+	b = append(b, r.RewardAsset[:]...)
+	b = binary.LittleEndian.AppendUint64(b, r.RewardAmount)
+	b = binary.LittleEndian.AppendUint64(b, r.FinalityDelaySeconds)
+	b = binary.LittleEndian.AppendUint64(b, r.Nonce)
+	b = binary.LittleEndian.AppendUint64(b, r.Expiry)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(r.ExtraData)))
+	for _, data := range r.ExtraData {
+		b = appendBytes(b, data)
+	}
+	return b
+}
+
+// Hash returns the request hash the program checks fulfill against, the
+// keccak256 of the Borsh-encoded request. The fulfillment info account is
+// derived from it.
+func (r *Request) Hash() common.Hash {
+	return crypto.Keccak256Hash(r.Borsh())
+}
+
+func appendBytes(b, data []byte) []byte {
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(data)))
+	return append(b, data...)
+}
+
+func toUint64(v *big.Int) (uint64, error) {
+	if v == nil {
+		return 0, nil
+	}
+	if !v.IsUint64() {
+		return 0, fmt.Errorf("%s doesn't fit in a u64", v)
+	}
+	return v.Uint64(), nil
+}
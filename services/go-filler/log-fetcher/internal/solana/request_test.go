@@ -0,0 +1,108 @@
+package solana
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/base-org/RIP-7755-poc/services/go-filler/bindings"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/ids"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+var outbox = common.HexToAddress("0x5555555555555555555555555555555555555555")
+
+func newLog() *bindings.RIP7755OutboxCrossChainCallRequested {
+	return &bindings.RIP7755OutboxCrossChainCallRequested{
+		Request: bindings.CrossChainRequest{
+			Requester:            common.HexToAddress("0x1111111111111111111111111111111111111111"),
+			Calls:                []bindings.Call{{To: common.HexToAddress("0x2222222222222222222222222222222222222222"), Data: []byte{}, Value: big.NewInt(1_000_000)}},
+			DestinationChainId:   big.NewInt(103),
+			InboxContract:        common.HexToAddress("0x3333333333333333333333333333333333333333"),
+			RewardAsset:          common.HexToAddress("0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE"),
+			RewardAmount:         big.NewInt(2_000_000),
+			FinalityDelaySeconds: big.NewInt(10),
+			Nonce:                big.NewInt(1),
+			Expiry:               big.NewInt(1_700_000_000),
+		},
+		Raw: types.Log{Address: outbox},
+	}
+}
+
+func TestFromLog(t *testing.T) {
+	log := newLog()
+
+	r, err := FromLog(log, big.NewInt(421614))
+
+	assert.NoError(t, err)
+	assert.Equal(t, ids.Pubkey(ids.AddressToBytes32(log.Request.Requester)), r.Requester)
+	assert.Equal(t, ids.Pubkey(ids.AddressToBytes32(outbox)), r.Origin)
+	assert.Equal(t, uint64(421614), r.SourceChainId)
+	assert.Equal(t, uint64(103), r.DestinationChainId)
+	assert.Equal(t, []Call{{To: ids.Pubkey(ids.AddressToBytes32(log.Request.Calls[0].To)), Data: []byte{}, Value: 1_000_000}}, r.Calls)
+	assert.Equal(t, uint64(2_000_000), r.RewardAmount)
+	assert.Empty(t, r.ExtraData)
+	assert.Equal(t, big.NewInt(1_000_000), r.TotalValue())
+}
+
+func TestFromLogPrecheck(t *testing.T) {
+	log := newLog()
+	log.Request.PrecheckContract = common.HexToAddress("0x4444444444444444444444444444444444444444")
+	log.Request.PrecheckData = []byte{0xaa}
+
+	r, err := FromLog(log, big.NewInt(421614))
+
+	assert.NoError(t, err)
+	precheck := ids.AddressToBytes32(log.Request.PrecheckContract)
+	assert.Equal(t, [][]byte{append(precheck[:], 0xaa)}, r.ExtraData)
+}
+
+func TestFromLogOutOfRange(t *testing.T) {
+	log := newLog()
+	log.Request.RewardAmount = new(big.Int).Lsh(big.NewInt(1), 64)
+
+	_, err := FromLog(log, big.NewInt(421614))
+	assert.ErrorContains(t, err, "reward amount")
+
+	log = newLog()
+	log.Request.Calls[0].Value = big.NewInt(-1)
+
+	_, err = FromLog(log, big.NewInt(421614))
+	assert.ErrorContains(t, err, "call 0 value")
+}
+
+func TestBorsh(t *testing.T) {
+	r := &Request{
+		Requester:          ids.Pubkey{1},
+		Calls:              []Call{{To: ids.Pubkey{2}, Data: []byte{0xab, 0xcd}, Value: 3}},
+		SourceChainId:      4,
+		Origin:             ids.Pubkey{5},
+		DestinationChainId: 6,
+		InboxContract:      ids.Pubkey{7},
+		L2Oracle:           ids.Pubkey{8},
+		L2OracleStorageKey: [32]byte{9},
+		RewardAsset:        ids.Pubkey{10},
+		RewardAmount:       11,
+		Nonce:              12,
+		Expiry:             13,
+		ExtraData:          [][]byte{{14}},
+	}
+
+	key := func(b byte) []byte { return append([]byte{b}, make([]byte, 31)...) }
+	u32 := func(v byte) []byte { return []byte{v, 0, 0, 0} }
+	u64 := func(v byte) []byte { return []byte{v, 0, 0, 0, 0, 0, 0, 0} }
+
+	expected := bytes.Join([][]byte{
+		key(1),
+		u32(1), key(2), u32(2), {0xab, 0xcd}, u64(3),
+		u64(4), key(5), u64(6), key(7), key(8), key(9), key(10),
+		u64(11), u64(0), u64(12), u64(13),
+		u32(1), u32(1), {14},
+	}, nil)
+
+	assert.Equal(t, expected, r.Borsh())
+	assert.Equal(t, crypto.Keccak256Hash(expected), r.Hash())
+}
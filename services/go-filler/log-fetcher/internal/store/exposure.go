@@ -0,0 +1,67 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Commitment is what a pending job commits of the fulfiller's capital on its
+// destination chain: the native call value and the ERC-20 amounts its calls
+// transfer, by token.
+type Commitment struct {
+	RequestHash [32]byte
+	CallValue   *big.Int
+	TokenValues map[common.Address]*big.Int
+}
+
+// Pending returns the commitments of the pending jobs for the given
+// destination chain. Fulfilled jobs are already reflected in the fulfiller's
+// balances and aren't returned.
+func (q *queue) Pending(dstChainId string) ([]Commitment, error) {
+	ctx := context.TODO()
+	query := bson.M{"destinationchainid": dstChainId, "status": StatusPending}
+	opts := options.Find().SetProjection(bson.M{"requesthash": 1, "callvalue": 1, "tokenvalues": 1})
+
+	cursor, err := q.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var commitments []Commitment
+	for cursor.Next(ctx) {
+		var r struct {
+			RequestHash [32]byte
+			CallValue   string
+			TokenValues map[string]string
+		}
+		if err := cursor.Decode(&r); err != nil {
+			return nil, err
+		}
+
+		c := Commitment{RequestHash: r.RequestHash, CallValue: new(big.Int)}
+		if r.CallValue != "" {
+			if _, ok := c.CallValue.SetString(r.CallValue, 10); !ok {
+				return nil, fmt.Errorf("invalid call value %q", r.CallValue)
+			}
+		}
+		for token, amount := range r.TokenValues {
+			value, ok := new(big.Int).SetString(amount, 10)
+			if !ok || !common.IsHexAddress(token) {
+				return nil, fmt.Errorf("invalid token value %s %q", token, amount)
+			}
+			if c.TokenValues == nil {
+				c.TokenValues = map[common.Address]*big.Int{}
+			}
+			c.TokenValues[common.HexToAddress(token)] = value
+		}
+		commitments = append(commitments, c)
+	}
+
+	return commitments, cursor.Err()
+}
@@ -0,0 +1,60 @@
+package store
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestPending(t *testing.T) {
+	mockConnection := new(MongoConnectionMock)
+	queue := &queue{collection: mockConnection}
+	usdc := common.HexToAddress("0x036CbD53842c5426634e7929541eC2318f3dCF7e")
+
+	cursor, _ := mongo.NewCursorFromDocuments([]interface{}{
+		bson.M{"requesthash": [32]byte{0x01}, "callvalue": "1000"},
+		bson.M{"requesthash": [32]byte{0x02}, "callvalue": "250", "tokenvalues": bson.M{usdc.Hex(): "5000000"}},
+		bson.M{"requesthash": [32]byte{0x03}},
+	}, nil, nil)
+	mockConnection.On("Find", mock.Anything, bson.M{"destinationchainid": "84532", "status": StatusPending}, mock.Anything).Return(cursor, nil)
+
+	pending, err := queue.Pending("84532")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Commitment{
+		{RequestHash: [32]byte{0x01}, CallValue: big.NewInt(1000)},
+		{RequestHash: [32]byte{0x02}, CallValue: big.NewInt(250), TokenValues: map[common.Address]*big.Int{usdc: big.NewInt(5_000_000)}},
+		{RequestHash: [32]byte{0x03}, CallValue: new(big.Int)},
+	}, pending)
+}
+
+func TestPendingRejectsInvalidValue(t *testing.T) {
+	mockConnection := new(MongoConnectionMock)
+	queue := &queue{collection: mockConnection}
+
+	cursor, _ := mongo.NewCursorFromDocuments([]interface{}{
+		bson.M{"callvalue": "1000", "tokenvalues": bson.M{"0x036CbD53842c5426634e7929541eC2318f3dCF7e": "lots"}},
+	}, nil, nil)
+	mockConnection.On("Find", mock.Anything, mock.Anything, mock.Anything).Return(cursor, nil)
+
+	_, err := queue.Pending("84532")
+
+	assert.ErrorContains(t, err, "invalid token value")
+}
+
+func TestPendingReturnsFindError(t *testing.T) {
+	mockConnection := new(MongoConnectionMock)
+	queue := &queue{collection: mockConnection}
+
+	mockConnection.On("Find", mock.Anything, mock.Anything, mock.Anything).Return((*mongo.Cursor)(nil), errors.New("test error"))
+
+	_, err := queue.Pending("84532")
+
+	assert.Error(t, err)
+}
@@ -2,19 +2,29 @@ package store
 
 import (
 	"context"
+	"fmt"
+	"math/big"
+	"time"
 
 	"github.com/base-org/RIP-7755-poc/services/go-filler/bindings"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/ids"
+	"github.com/ethereum/go-ethereum/common"
 	logger "github.com/ethereum/go-ethereum/log"
 	"github.com/urfave/cli/v2"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type Queue interface {
-	Enqueue(*bindings.RIP7755OutboxCrossChainCallRequested) error
+	Enqueue(*bindings.RIP7755OutboxCrossChainCallRequested, JobInfo) error
 	ReadCheckpoint(checkpointId string) (uint64, error)
 	WriteCheckpoint(checkpointId string, blockNumber uint64) error
+	WriteRejection(Rejection) error
+	RecordRequester(requester common.Address, outcome RequesterOutcome) error
+	Pending(dstChainId string) ([]Commitment, error)
+	Subscribe(ctx context.Context, filter Filter) (<-chan Event, error)
 	Close() error
 }
 
@@ -22,6 +32,8 @@ type MongoCollection interface {
 	InsertOne(ctx context.Context, document interface{}, opts ...*options.InsertOneOptions) (*mongo.InsertOneResult, error)
 	UpdateOne(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error)
 	FindOne(ctx context.Context, filter interface{}, opts ...*options.FindOneOptions) *mongo.SingleResult
+	Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error)
+	Watch(ctx context.Context, pipeline interface{}, opts ...*options.ChangeStreamOptions) (*mongo.ChangeStream, error)
 }
 
 type MongoDriverClient interface {
@@ -30,14 +42,91 @@ type MongoDriverClient interface {
 }
 
 type queue struct {
-	client     MongoDriverClient
-	collection MongoCollection
-	checkpoint MongoCollection
+	client       MongoDriverClient
+	collection   MongoCollection
+	checkpoint   MongoCollection
+	rejections   MongoCollection
+	requesters   MongoCollection
+	pollInterval time.Duration
+}
+
+// JobInfo holds details derived while validating a request that are stored
+// alongside it. Source, Destination and Requester are the CAIP ids of the
+// route and the requester. NormalizedReward is the reward scaled to 18
+// decimals, CallValue the native value the fulfiller commits on the
+// destination chain and TokenValues the ERC-20 amounts, by token. Solana is
+// set for requests bound for a Solana chain.
+type JobInfo struct {
+	Source           ids.ChainID
+	Destination      ids.ChainID
+	Requester        ids.Account
+	RewardAsset      common.Address
+	RewardSymbol     string
+	RewardAmount     *big.Int
+	NormalizedReward *big.Int
+	CallValue        *big.Int
+	TokenValues      map[common.Address]*big.Int
+	Simulation       *Simulation
+	Solana           *SolanaJob
+}
+
+// SolanaJob is what a Solana executor needs to fulfill a request on the
+// inbox program. Request is the Borsh encoded request as the program
+// deserializes it, RequestHash its keccak256 which seeds the fulfillment_info
+// account. Calls repeat the decoded calls with base58 targets.
+type SolanaJob struct {
+	ProgramId   string
+	RequestHash string
+	Request     string
+	Calls       []SolanaCall
+}
+
+type SolanaCall struct {
+	To    string
+	Data  string
+	Value string
+}
+
+// Simulation is the outcome of simulating the fulfillment on the destination
+// chain before the request was accepted.
+type Simulation struct {
+	Success      bool
+	Result       string
+	GasUsed      uint64
+	RevertReason string
 }
 
 type record struct {
-	RequestHash [32]byte
-	Request     bindings.CrossChainRequest
+	ID                 primitive.ObjectID `bson:"_id,omitempty"`
+	RequestHash        [32]byte
+	Request            bindings.CrossChainRequest
+	DestinationChainId string
+	SourceChain        string
+	DestinationChain   string
+	Requester          string
+	CallValue          string
+	TokenValues        map[string]string `bson:",omitempty"`
+	Status             Status
+	RewardAsset        string
+	RewardSymbol       string
+	RewardAmount       string
+	NormalizedReward   primitive.Decimal128
+	Simulation         *Simulation
+	Solana             *SolanaJob
+	UpdatedAt          time.Time
+}
+
+// Rejection records why a request was passed on. Code is the validator's
+// reason code, the remaining fields carry its structured details if any.
+type Rejection struct {
+	RequestHash   [32]byte
+	SourceChainId string
+	Code          string
+	Expected      string
+	Actual        string
+	Required      string
+	Offered       string
+	CreatedAt     time.Time
 }
 
 type checkpoint struct {
@@ -50,17 +139,59 @@ func NewQueue(ctx *cli.Context) (Queue, error) {
 		return nil, err
 	}
 
-	return &queue{client: client, collection: client.Database("calls").Collection("requests"), checkpoint: client.Database("calls").Collection("checkpoint")}, nil
+	return &queue{
+		client:       client,
+		collection:   client.Database("calls").Collection("requests"),
+		checkpoint:   client.Database("calls").Collection("checkpoint"),
+		rejections:   client.Database("calls").Collection("rejections"),
+		requesters:   client.Database("calls").Collection("requesters"),
+		pollInterval: time.Second,
+	}, nil
 }
 
-func (q *queue) Enqueue(log *bindings.RIP7755OutboxCrossChainCallRequested) error {
+func (q *queue) Enqueue(log *bindings.RIP7755OutboxCrossChainCallRequested, info JobInfo) error {
 	logger.Info("Sending job to queue")
 
 	r := record{
-		RequestHash: log.RequestHash,
-		Request:     log.Request,
+		RequestHash:      log.RequestHash,
+		Request:          log.Request,
+		SourceChain:      info.Source.String(),
+		DestinationChain: info.Destination.String(),
+		Requester:        info.Requester.String(),
+		Status:           StatusPending,
+		RewardAsset:      info.RewardAsset.Hex(),
+		RewardSymbol:     info.RewardSymbol,
+		Simulation:       info.Simulation,
+		Solana:           info.Solana,
+		UpdatedAt:        time.Now(),
+	}
+	if log.Request.DestinationChainId != nil {
+		r.DestinationChainId = log.Request.DestinationChainId.String()
+	}
+	if info.CallValue != nil {
+		r.CallValue = info.CallValue.String()
+	}
+	for token, amount := range info.TokenValues {
+		if r.TokenValues == nil {
+			r.TokenValues = map[string]string{}
+		}
+		r.TokenValues[token.Hex()] = amount.String()
 	}
-	_, err := q.collection.InsertOne(context.TODO(), r)
+	if info.RewardAmount != nil {
+		r.RewardAmount = info.RewardAmount.String()
+	}
+	if info.NormalizedReward != nil {
+		normalized, ok := primitive.ParseDecimal128FromBigInt(info.NormalizedReward, 0)
+		if !ok {
+			return fmt.Errorf("reward %s out of range", info.NormalizedReward)
+		}
+		r.NormalizedReward = normalized
+	}
+
+	// A log handled again after a restart is already queued, and its job may
+	// have moved on, so an existing record is left as it is.
+	opts := options.Update().SetUpsert(true)
+	_, err := q.collection.UpdateOne(context.TODO(), bson.M{"requesthash": r.RequestHash}, bson.M{"$setOnInsert": r}, opts)
 	if err != nil {
 		return err
 	}
@@ -101,6 +232,19 @@ func (q *queue) WriteCheckpoint(checkpointId string, blockNumber uint64) error {
 	return nil
 }
 
+func (q *queue) WriteRejection(r Rejection) error {
+	if r.CreatedAt.IsZero() {
+		r.CreatedAt = time.Now()
+	}
+
+	_, err := q.rejections.InsertOne(context.TODO(), r)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (q *queue) Close() error {
 	return q.client.Disconnect(context.TODO())
 }
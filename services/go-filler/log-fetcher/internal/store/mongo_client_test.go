@@ -3,11 +3,16 @@ package store
 import (
 	"context"
 	"errors"
+	"math/big"
+	"reflect"
 	"testing"
 
 	"github.com/base-org/RIP-7755-poc/services/go-filler/bindings"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/ids"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -35,6 +40,16 @@ func (c *MongoConnectionMock) FindOne(ctx context.Context, filter interface{}, o
 	return args.Get(0).(*mongo.SingleResult)
 }
 
+func (c *MongoConnectionMock) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error) {
+	args := c.Called(ctx, filter, opts)
+	return args.Get(0).(*mongo.Cursor), args.Error(1)
+}
+
+func (c *MongoConnectionMock) Watch(ctx context.Context, pipeline interface{}, opts ...*options.ChangeStreamOptions) (*mongo.ChangeStream, error) {
+	args := c.Called(ctx, pipeline, opts)
+	return args.Get(0).(*mongo.ChangeStream), args.Error(1)
+}
+
 func (m *MongoClientMock) Database(name string, opts ...*options.DatabaseOptions) *mongo.Database {
 	args := m.Called(name, opts)
 	return args.Get(0).(*mongo.Database)
@@ -49,25 +64,129 @@ func TestEnqueue(t *testing.T) {
 	mockConnection := new(MongoConnectionMock)
 	queue := &queue{collection: mockConnection}
 
-	mockConnection.On("InsertOne", mock.Anything, mock.Anything, mock.Anything).Return(&mongo.InsertOneResult{}, nil)
+	mockConnection.On("UpdateOne", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&mongo.UpdateResult{}, nil)
 
-	err := queue.Enqueue(&bindings.RIP7755OutboxCrossChainCallRequested{})
+	err := queue.Enqueue(&bindings.RIP7755OutboxCrossChainCallRequested{}, JobInfo{})
 
 	assert.NoError(t, err)
 }
 
-func TestEnqueuePassesParsedLogToInsertOne(t *testing.T) {
+func TestEnqueuePassesParsedLogToUpsert(t *testing.T) {
 	mockConnection := new(MongoConnectionMock)
 	queue := &queue{collection: mockConnection}
 	log := &bindings.RIP7755OutboxCrossChainCallRequested{}
-	r := record{
-		RequestHash: log.RequestHash,
-		Request:     log.Request,
+	matchesLog := inserted(func(r record) bool {
+		return r.RequestHash == log.RequestHash &&
+			reflect.DeepEqual(r.Request, log.Request) &&
+			r.Status == StatusPending &&
+			!r.UpdatedAt.IsZero()
+	})
+
+	mockConnection.On("UpdateOne", context.TODO(), mock.Anything, matchesLog, mock.Anything).Return(&mongo.UpdateResult{}, nil)
+
+	err := queue.Enqueue(log, JobInfo{})
+
+	assert.NoError(t, err)
+	mockConnection.AssertExpectations(t)
+}
+
+func TestEnqueueStoresRewardInfo(t *testing.T) {
+	mockConnection := new(MongoConnectionMock)
+	queue := &queue{collection: mockConnection}
+	info := JobInfo{
+		RewardAsset:      common.HexToAddress("0x75faf114eafb1BDbe2F0316DF893fd58CE46AA4d"),
+		RewardSymbol:     "USDC",
+		RewardAmount:     big.NewInt(5_000_000),
+		NormalizedReward: new(big.Int).Mul(big.NewInt(5), big.NewInt(1e18)),
 	}
+	matchesInfo := inserted(func(r record) bool {
+		return r.RewardAsset == info.RewardAsset.Hex() &&
+			r.RewardSymbol == "USDC" &&
+			r.RewardAmount == "5000000" &&
+			r.NormalizedReward.String() == "5000000000000000000"
+	})
+
+	mockConnection.On("UpdateOne", context.TODO(), mock.Anything, matchesInfo, mock.Anything).Return(&mongo.UpdateResult{}, nil)
+
+	err := queue.Enqueue(&bindings.RIP7755OutboxCrossChainCallRequested{}, info)
+
+	assert.NoError(t, err)
+	mockConnection.AssertExpectations(t)
+}
+
+func TestEnqueueStoresIds(t *testing.T) {
+	mockConnection := new(MongoConnectionMock)
+	queue := &queue{collection: mockConnection}
+	info := JobInfo{
+		Source:      ids.EVMChain(big.NewInt(421614)),
+		Destination: ids.EVMChain(big.NewInt(84532)),
+		Requester:   ids.EVMAccount(big.NewInt(421614), common.HexToAddress("0x1111111111111111111111111111111111111111")),
+	}
+	matchesIds := inserted(func(r record) bool {
+		return r.SourceChain == "eip155:421614" &&
+			r.DestinationChain == "eip155:84532" &&
+			r.Requester == "eip155:421614:0x1111111111111111111111111111111111111111"
+	})
+
+	mockConnection.On("UpdateOne", context.TODO(), mock.Anything, matchesIds, mock.Anything).Return(&mongo.UpdateResult{}, nil)
+
+	err := queue.Enqueue(&bindings.RIP7755OutboxCrossChainCallRequested{}, info)
+
+	assert.NoError(t, err)
+	mockConnection.AssertExpectations(t)
+}
+
+func TestEnqueueStoresSolanaJob(t *testing.T) {
+	mockConnection := new(MongoConnectionMock)
+	queue := &queue{collection: mockConnection}
+	job := &SolanaJob{
+		ProgramId:   "2nfLnXeeWyAUBsCT8uskj8nvkk46FiwaaVvDx29zQcue",
+		RequestHash: "0x01",
+		Request:     "0x02",
+		Calls:       []SolanaCall{{To: "11111111111111111111111111111111", Data: "0x", Value: "1000"}},
+	}
+	matchesJob := inserted(func(r record) bool {
+		return r.Solana == job
+	})
+
+	mockConnection.On("UpdateOne", context.TODO(), mock.Anything, matchesJob, mock.Anything).Return(&mongo.UpdateResult{}, nil)
+
+	err := queue.Enqueue(&bindings.RIP7755OutboxCrossChainCallRequested{}, JobInfo{Solana: job})
+
+	assert.NoError(t, err)
+	mockConnection.AssertExpectations(t)
+}
 
-	mockConnection.On("InsertOne", context.TODO(), r, mock.Anything).Return(&mongo.InsertOneResult{}, nil)
+func TestEnqueueStoresSimulation(t *testing.T) {
+	mockConnection := new(MongoConnectionMock)
+	queue := &queue{collection: mockConnection}
+	info := JobInfo{Simulation: &Simulation{Success: true, Result: "0x", GasUsed: 210000}}
+	matchesInfo := inserted(func(r record) bool {
+		return r.Simulation != nil && r.Simulation.Success && r.Simulation.GasUsed == 210000
+	})
+
+	mockConnection.On("UpdateOne", context.TODO(), mock.Anything, matchesInfo, mock.Anything).Return(&mongo.UpdateResult{}, nil)
 
-	err := queue.Enqueue(log)
+	err := queue.Enqueue(&bindings.RIP7755OutboxCrossChainCallRequested{}, info)
+
+	assert.NoError(t, err)
+	mockConnection.AssertExpectations(t)
+}
+
+func TestEnqueueStoresCommittedValue(t *testing.T) {
+	mockConnection := new(MongoConnectionMock)
+	queue := &queue{collection: mockConnection}
+	log := &bindings.RIP7755OutboxCrossChainCallRequested{}
+	log.Request.DestinationChainId = big.NewInt(84532)
+	usdc := common.HexToAddress("0x036CbD53842c5426634e7929541eC2318f3dCF7e")
+	matchesInfo := inserted(func(r record) bool {
+		return r.DestinationChainId == "84532" && r.CallValue == "1000" &&
+			reflect.DeepEqual(map[string]string{usdc.Hex(): "5000000"}, r.TokenValues)
+	})
+
+	mockConnection.On("UpdateOne", context.TODO(), mock.Anything, matchesInfo, mock.Anything).Return(&mongo.UpdateResult{}, nil)
+
+	err := queue.Enqueue(log, JobInfo{CallValue: big.NewInt(1000), TokenValues: map[common.Address]*big.Int{usdc: big.NewInt(5_000_000)}})
 
 	assert.NoError(t, err)
 	mockConnection.AssertExpectations(t)
@@ -77,13 +196,38 @@ func TestEnqueueError(t *testing.T) {
 	mockConnection := new(MongoConnectionMock)
 	queue := &queue{collection: mockConnection}
 
-	mockConnection.On("InsertOne", mock.Anything, mock.Anything, mock.Anything).Return(&mongo.InsertOneResult{}, errors.New("error"))
+	mockConnection.On("UpdateOne", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&mongo.UpdateResult{}, errors.New("error"))
 
-	err := queue.Enqueue(&bindings.RIP7755OutboxCrossChainCallRequested{})
+	err := queue.Enqueue(&bindings.RIP7755OutboxCrossChainCallRequested{}, JobInfo{})
 
 	assert.Error(t, err)
 }
 
+func TestEnqueueKeepsQueuedJob(t *testing.T) {
+	mockConnection := new(MongoConnectionMock)
+	queue := &queue{collection: mockConnection}
+	log := &bindings.RIP7755OutboxCrossChainCallRequested{RequestHash: [32]byte{0x01}}
+	byHash := bson.M{"requesthash": log.RequestHash}
+	upsert := mock.MatchedBy(func(opts []*options.UpdateOptions) bool {
+		return len(opts) == 1 && *opts[0].Upsert
+	})
+
+	mockConnection.On("UpdateOne", context.TODO(), byHash, inserted(func(r record) bool { return true }), upsert).Return(&mongo.UpdateResult{}, nil)
+
+	err := queue.Enqueue(log, JobInfo{})
+
+	assert.NoError(t, err)
+	mockConnection.AssertExpectations(t)
+}
+
+// inserted matches an upsert that only inserts a record, matching match.
+func inserted(match func(r record) bool) interface{} {
+	return mock.MatchedBy(func(update bson.M) bool {
+		r, ok := update["$setOnInsert"].(record)
+		return ok && len(update) == 1 && match(r)
+	})
+}
+
 func TestReadCheckpoint(t *testing.T) {
 	mockConnection := new(MongoConnectionMock)
 	queue := &queue{checkpoint: mockConnection}
@@ -109,6 +253,32 @@ func TestWriteCheckpoint(t *testing.T) {
 	mockConnection.AssertExpectations(t)
 }
 
+func TestWriteRejection(t *testing.T) {
+	mockConnection := new(MongoConnectionMock)
+	queue := &queue{rejections: mockConnection}
+	matchesRejection := mock.MatchedBy(func(r Rejection) bool {
+		return r.Code == "unknown_prover" && r.SourceChainId == "421614" && !r.CreatedAt.IsZero()
+	})
+
+	mockConnection.On("InsertOne", context.TODO(), matchesRejection, mock.Anything).Return(&mongo.InsertOneResult{}, nil)
+
+	err := queue.WriteRejection(Rejection{SourceChainId: "421614", Code: "unknown_prover"})
+
+	assert.NoError(t, err)
+	mockConnection.AssertExpectations(t)
+}
+
+func TestWriteRejectionError(t *testing.T) {
+	mockConnection := new(MongoConnectionMock)
+	queue := &queue{rejections: mockConnection}
+
+	mockConnection.On("InsertOne", mock.Anything, mock.Anything, mock.Anything).Return(&mongo.InsertOneResult{}, errors.New("error"))
+
+	err := queue.WriteRejection(Rejection{})
+
+	assert.Error(t, err)
+}
+
 func TestClose(t *testing.T) {
 	mockClient := new(MongoClientMock)
 	queue := &queue{client: mockClient}
@@ -0,0 +1,35 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// RequesterOutcome is what became of a request, as counted in the requester's
+// statistics.
+type RequesterOutcome string
+
+const (
+	OutcomeAccepted  RequesterOutcome = "accepted"
+	OutcomeRejected  RequesterOutcome = "rejected"
+	OutcomeThrottled RequesterOutcome = "throttled"
+	OutcomeBlocked   RequesterOutcome = "blocked"
+)
+
+// RecordRequester counts a request against its requester. Each requester has
+// one document in the requesters collection with a total under seen, a count
+// per outcome and the time it was last seen.
+func (q *queue) RecordRequester(requester common.Address, outcome RequesterOutcome) error {
+	update := bson.M{
+		"$inc": bson.M{"seen": 1, string(outcome): 1},
+		"$set": bson.M{"lastseen": time.Now()},
+	}
+	opts := options.Update().SetUpsert(true)
+
+	_, err := q.requesters.UpdateOne(context.TODO(), bson.M{"requester": requester.Hex()}, update, opts)
+	return err
+}
@@ -0,0 +1,42 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var requester = common.HexToAddress("0x1111111111111111111111111111111111111111")
+
+func TestRecordRequester(t *testing.T) {
+	mockConnection := new(MongoConnectionMock)
+	queue := &queue{requesters: mockConnection}
+	matchesUpdate := mock.MatchedBy(func(update bson.M) bool {
+		inc := update["$inc"].(bson.M)
+		return inc["seen"] == 1 && inc["throttled"] == 1
+	})
+
+	mockConnection.On("UpdateOne", context.TODO(), bson.M{"requester": requester.Hex()}, matchesUpdate, mock.Anything).Return(&mongo.UpdateResult{}, nil)
+
+	err := queue.RecordRequester(requester, OutcomeThrottled)
+
+	assert.NoError(t, err)
+	mockConnection.AssertExpectations(t)
+}
+
+func TestRecordRequesterError(t *testing.T) {
+	mockConnection := new(MongoConnectionMock)
+	queue := &queue{requesters: mockConnection}
+
+	mockConnection.On("UpdateOne", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&mongo.UpdateResult{}, errors.New("error"))
+
+	err := queue.RecordRequester(requester, OutcomeAccepted)
+
+	assert.Error(t, err)
+}
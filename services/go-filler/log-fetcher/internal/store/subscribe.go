@@ -0,0 +1,214 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/base-org/RIP-7755-poc/services/go-filler/bindings"
+	logger "github.com/ethereum/go-ethereum/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Status is the lifecycle state of a job in the requests collection. Each
+// downstream worker subscribes to the status it is responsible for.
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusFulfilled Status = "fulfilled"
+	StatusProven    Status = "proven"
+	StatusClaimed   Status = "claimed"
+)
+
+// Filter narrows a subscription down to the jobs a worker cares about. An empty
+// filter matches every job. Destinations are CAIP-2 chain ids, e.g. to only
+// pick up jobs bound for a Solana chain.
+type Filter struct {
+	Statuses     []Status
+	Destinations []string
+}
+
+// Event is emitted whenever a job is inserted into the requests collection or
+// changes state.
+type Event struct {
+	RequestHash      [32]byte
+	Request          bindings.CrossChainRequest
+	DestinationChain string
+	Solana           *SolanaJob
+	Status           Status
+	UpdatedAt        time.Time
+}
+
+type changeEvent struct {
+	FullDocument record `bson:"fullDocument"`
+}
+
+// errChangeStreamsUnsupported is the server error code for opening a change
+// stream on a standalone server.
+const errChangeStreamsUnsupported = 40573
+
+// Subscribe streams job events matching filter until ctx is cancelled. It uses
+// Mongo change streams when available and falls back to polling on standalone
+// servers, as change streams are only supported on replica sets.
+func (q *queue) Subscribe(ctx context.Context, filter Filter) (<-chan Event, error) {
+	events := make(chan Event)
+
+	opts := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+	stream, err := q.collection.Watch(ctx, filter.pipeline(), opts)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		var serverErr mongo.ServerError
+		if !errors.As(err, &serverErr) || !serverErr.HasErrorCode(errChangeStreamsUnsupported) {
+			return nil, err
+		}
+
+		logger.Warn("Change streams unavailable, falling back to polling", "error", err)
+		go q.poll(ctx, filter, events)
+		return events, nil
+	}
+
+	go q.watch(ctx, stream, events)
+
+	return events, nil
+}
+
+func (q *queue) watch(ctx context.Context, stream *mongo.ChangeStream, events chan<- Event) {
+	defer close(events)
+	defer stream.Close(context.Background())
+
+	for stream.Next(ctx) {
+		var c changeEvent
+		if err := stream.Decode(&c); err != nil {
+			logger.Error("Failed to decode change event", "error", err)
+			continue
+		}
+
+		if !send(ctx, events, c.FullDocument.event()) {
+			return
+		}
+	}
+
+	if err := stream.Err(); err != nil && ctx.Err() == nil {
+		logger.Error("Change stream closed", "error", err)
+	}
+}
+
+func (q *queue) poll(ctx context.Context, filter Filter, events chan<- Event) {
+	defer close(events)
+
+	interval := q.pollInterval
+	if interval == 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// Jobs can share an update time, and one may land in the same millisecond
+	// after a poll, so jobs updated at since are polled again and those already
+	// sent skipped.
+	since := time.Now()
+	sent := make(map[primitive.ObjectID]bool)
+
+	for {
+		select {
+		case <-ticker.C:
+			err := q.pollOnce(ctx, filter, &since, sent, events)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				logger.Error("Failed to poll for jobs", "error", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// pollOnce sends the jobs updated since the last poll, moving since up to the
+// latest update time sent.
+func (q *queue) pollOnce(ctx context.Context, filter Filter, since *time.Time, sent map[primitive.ObjectID]bool, events chan<- Event) error {
+	opts := options.Find().SetSort(bson.D{{Key: "updatedat", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := q.collection.Find(ctx, filter.query(*since), opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(context.Background())
+
+	for cursor.Next(ctx) {
+		var r record
+		if err := cursor.Decode(&r); err != nil {
+			logger.Error("Failed to decode job", "error", err)
+			continue
+		}
+
+		if r.UpdatedAt.Equal(*since) && sent[r.ID] {
+			continue
+		}
+
+		if !send(ctx, events, r.event()) {
+			return ctx.Err()
+		}
+
+		if r.UpdatedAt.After(*since) {
+			*since = r.UpdatedAt
+			clear(sent)
+		}
+		if r.UpdatedAt.Equal(*since) {
+			sent[r.ID] = true
+		}
+	}
+
+	return cursor.Err()
+}
+
+func send(ctx context.Context, events chan<- Event, e Event) bool {
+	select {
+	case events <- e:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (r record) event() Event {
+	return Event{
+		RequestHash:      r.RequestHash,
+		Request:          r.Request,
+		DestinationChain: r.DestinationChain,
+		Solana:           r.Solana,
+		Status:           r.Status,
+		UpdatedAt:        r.UpdatedAt,
+	}
+}
+
+func (f Filter) pipeline() mongo.Pipeline {
+	match := bson.D{{Key: "operationType", Value: bson.M{"$in": []string{"insert", "update", "replace"}}}}
+	if len(f.Statuses) > 0 {
+		match = append(match, bson.E{Key: "fullDocument.status", Value: bson.M{"$in": f.Statuses}})
+	}
+	if len(f.Destinations) > 0 {
+		match = append(match, bson.E{Key: "fullDocument.destinationchain", Value: bson.M{"$in": f.Destinations}})
+	}
+
+	return mongo.Pipeline{{{Key: "$match", Value: match}}}
+}
+
+func (f Filter) query(since time.Time) bson.M {
+	query := bson.M{"updatedat": bson.M{"$gte": since}}
+	if len(f.Statuses) > 0 {
+		query["status"] = bson.M{"$in": f.Statuses}
+	}
+	if len(f.Destinations) > 0 {
+		query["destinationchain"] = bson.M{"$in": f.Destinations}
+	}
+
+	return query
+}
@@ -0,0 +1,180 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var standalone = mongo.CommandError{Code: errChangeStreamsUnsupported, Message: "The $changeStream stage is only supported on replica sets"}
+
+func TestSubscribeFallsBackToPolling(t *testing.T) {
+	mockConnection := new(MongoConnectionMock)
+	queue := &queue{collection: mockConnection, pollInterval: time.Millisecond}
+
+	r := record{RequestHash: [32]byte{1}, Status: StatusPending, UpdatedAt: time.Now().Add(time.Minute)}
+	cursor, err := mongo.NewCursorFromDocuments([]interface{}{r}, nil, nil)
+	assert.NoError(t, err)
+
+	mockConnection.On("Watch", mock.Anything, mock.Anything, mock.Anything).Return((*mongo.ChangeStream)(nil), standalone)
+	mockConnection.On("Find", mock.Anything, mock.Anything, mock.Anything).Return(cursor, nil).Once()
+	emptyCursor, _ := mongo.NewCursorFromDocuments(nil, nil, nil)
+	mockConnection.On("Find", mock.Anything, mock.Anything, mock.Anything).Return(emptyCursor, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := queue.Subscribe(ctx, Filter{Statuses: []Status{StatusPending}})
+	assert.NoError(t, err)
+
+	select {
+	case e := <-events:
+		assert.Equal(t, r.RequestHash, e.RequestHash)
+		assert.Equal(t, StatusPending, e.Status)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event")
+	}
+}
+
+func TestPollingSendsJobsUpdatedTogetherOnce(t *testing.T) {
+	mockConnection := new(MongoConnectionMock)
+	queue := &queue{collection: mockConnection, pollInterval: time.Millisecond}
+
+	updatedAt := time.Now().Add(time.Minute).Truncate(time.Millisecond)
+	first := record{ID: primitive.NewObjectID(), RequestHash: [32]byte{1}, Status: StatusPending, UpdatedAt: updatedAt}
+	second := record{ID: primitive.NewObjectID(), RequestHash: [32]byte{2}, Status: StatusPending, UpdatedAt: updatedAt}
+	firstPoll, _ := mongo.NewCursorFromDocuments([]interface{}{first}, nil, nil)
+	secondPoll, _ := mongo.NewCursorFromDocuments([]interface{}{first, second}, nil, nil)
+
+	mockConnection.On("Watch", mock.Anything, mock.Anything, mock.Anything).Return((*mongo.ChangeStream)(nil), standalone)
+	mockConnection.On("Find", mock.Anything, mock.Anything, mock.Anything).Return(firstPoll, nil).Once()
+	mockConnection.On("Find", mock.Anything, mock.Anything, mock.Anything).Return(secondPoll, nil).Once()
+	emptyCursor, _ := mongo.NewCursorFromDocuments(nil, nil, nil)
+	mockConnection.On("Find", mock.Anything, mock.Anything, mock.Anything).Return(emptyCursor, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := queue.Subscribe(ctx, Filter{})
+	assert.NoError(t, err)
+
+	var hashes [][32]byte
+	timeout := time.After(200 * time.Millisecond)
+	for done := false; !done; {
+		select {
+		case e := <-events:
+			hashes = append(hashes, e.RequestHash)
+		case <-timeout:
+			done = true
+		}
+	}
+
+	assert.Equal(t, [][32]byte{first.RequestHash, second.RequestHash}, hashes)
+	// later polls include the jobs updated at the last update time seen
+	mockConnection.AssertCalled(t, "Find", mock.Anything, mock.MatchedBy(func(query bson.M) bool {
+		return query["updatedat"].(bson.M)["$gte"].(time.Time).Equal(updatedAt)
+	}), mock.Anything)
+}
+
+func TestSubscribeClosesChannelOnCancel(t *testing.T) {
+	mockConnection := new(MongoConnectionMock)
+	queue := &queue{collection: mockConnection, pollInterval: time.Hour}
+
+	mockConnection.On("Watch", mock.Anything, mock.Anything, mock.Anything).Return((*mongo.ChangeStream)(nil), standalone)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := queue.Subscribe(ctx, Filter{})
+	assert.NoError(t, err)
+
+	cancel()
+
+	select {
+	case _, ok := <-events:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for channel to close")
+	}
+}
+
+func TestSubscribeReturnsWatchError(t *testing.T) {
+	mockConnection := new(MongoConnectionMock)
+	queue := &queue{collection: mockConnection}
+
+	watchErr := mongo.CommandError{Code: 13, Message: "not authorized"}
+	mockConnection.On("Watch", mock.Anything, mock.Anything, mock.Anything).Return((*mongo.ChangeStream)(nil), watchErr)
+
+	events, err := queue.Subscribe(context.Background(), Filter{})
+
+	assert.Equal(t, watchErr, err)
+	assert.Nil(t, events)
+	mockConnection.AssertNotCalled(t, "Find", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPollReturnsCursorError(t *testing.T) {
+	mockConnection := new(MongoConnectionMock)
+	queue := &queue{collection: mockConnection}
+
+	cursorErr := errors.New("cursor killed")
+	cursor, _ := mongo.NewCursorFromDocuments(nil, cursorErr, nil)
+	mockConnection.On("Find", mock.Anything, mock.Anything, mock.Anything).Return(cursor, nil)
+
+	since := time.Now()
+	err := queue.pollOnce(context.Background(), Filter{}, &since, map[primitive.ObjectID]bool{}, make(chan Event))
+
+	assert.ErrorIs(t, err, cursorErr)
+}
+
+func TestSubscribeReturnsErrorWhenContextCancelled(t *testing.T) {
+	mockConnection := new(MongoConnectionMock)
+	queue := &queue{collection: mockConnection}
+
+	mockConnection.On("Watch", mock.Anything, mock.Anything, mock.Anything).Return((*mongo.ChangeStream)(nil), context.Canceled)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	events, err := queue.Subscribe(ctx, Filter{})
+
+	assert.Error(t, err)
+	assert.Nil(t, events)
+}
+
+func TestFilterPipeline(t *testing.T) {
+	pipeline := Filter{Statuses: []Status{StatusFulfilled}}.pipeline()
+
+	match := pipeline[0][0].Value.(bson.D)
+	assert.Equal(t, "operationType", match[0].Key)
+	assert.Equal(t, "fullDocument.status", match[1].Key)
+	assert.Equal(t, bson.M{"$in": []Status{StatusFulfilled}}, match[1].Value)
+}
+
+func TestFilterQuery(t *testing.T) {
+	since := time.Now()
+
+	assert.Equal(t, bson.M{"updatedat": bson.M{"$gte": since}}, Filter{}.query(since))
+	assert.Equal(t, bson.M{
+		"updatedat": bson.M{"$gte": since},
+		"status":    bson.M{"$in": []Status{StatusProven}},
+	}, Filter{Statuses: []Status{StatusProven}}.query(since))
+}
+
+func TestFilterDestinations(t *testing.T) {
+	since := time.Now()
+	filter := Filter{Destinations: []string{"solana:EtWTRABZaYq6iMfeYKouRu166VU2xqa1"}}
+
+	match := filter.pipeline()[0][0].Value.(bson.D)
+	assert.Equal(t, "fullDocument.destinationchain", match[1].Key)
+	assert.Equal(t, bson.M{"$in": filter.Destinations}, match[1].Value)
+
+	assert.Equal(t, bson.M{
+		"updatedat":        bson.M{"$gte": since},
+		"destinationchain": bson.M{"$in": filter.Destinations},
+	}, filter.query(since))
+}
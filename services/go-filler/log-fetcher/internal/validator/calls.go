@@ -0,0 +1,39 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/base-org/RIP-7755-poc/services/go-filler/bindings"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// validateCalls holds the request's calls to the destination chain's call
+// policy, since they run from our fulfiller.
+func (v *validator) validateCalls(ctx context.Context, req *Request) error {
+	calls, policy := req.Log.Request.Calls, &req.DstChain.CallPolicy
+
+	if policy.MaxCalls > 0 && len(calls) > policy.MaxCalls {
+		return &ValidationError{Code: ReasonTooManyCalls, Required: big.NewInt(int64(policy.MaxCalls)), Offered: big.NewInt(int64(len(calls)))}
+	}
+
+	for i, call := range calls {
+		if policy.MaxCallValue != nil && call.Value != nil && call.Value.Cmp(policy.MaxCallValue) > 0 {
+			return &ValidationError{Code: ReasonCallValueTooHigh, Required: policy.MaxCallValue, Offered: call.Value}
+		}
+
+		if !policy.Permits(call.To, call.Data) {
+			return &ValidationError{Code: ReasonCallNotPermitted, Actual: describeCall(i, call)}
+		}
+	}
+
+	return nil
+}
+
+func describeCall(i int, call bindings.Call) string {
+	if len(call.Data) < 4 {
+		return fmt.Sprintf("call %d to %s", i, call.To.Hex())
+	}
+	return fmt.Sprintf("call %d to %s selector %s", i, call.To.Hex(), hexutil.Encode(call.Data[:4]))
+}
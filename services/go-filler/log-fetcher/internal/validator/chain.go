@@ -0,0 +1,159 @@
+package validator
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/base-org/RIP-7755-poc/services/go-filler/bindings"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/attributes"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/chains"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/provers"
+	"github.com/ethereum/go-ethereum/common"
+	logger "github.com/ethereum/go-ethereum/log"
+)
+
+// Phase orders the stages of a ValidatorChain. Stages run phase by phase, and
+// in registration order within a phase.
+type Phase int
+
+const (
+	Structural Phase = iota
+	Route
+	Economic
+	Simulation
+	Custom
+)
+
+func (p Phase) String() string {
+	switch p {
+	case Structural:
+		return "structural"
+	case Route:
+		return "route"
+	case Economic:
+		return "economic"
+	case Simulation:
+		return "simulation"
+	case Custom:
+		return "custom"
+	default:
+		return "unknown"
+	}
+}
+
+// Request is a request under validation along with what earlier stages
+// learned about it. Structural stages resolve DstChain, the Prover for the
+// route, Attributes, CallValue, TokenValues and the reward in Result,
+// economic stages fill in the estimate and reward value, simulation stages the
+// simulation.
+type Request struct {
+	Log         *bindings.RIP7755OutboxCrossChainCallRequested
+	SrcChain    *chains.ChainConfig
+	DstChain    *chains.ChainConfig
+	Prover      provers.Prover
+	Attributes  attributes.Attributes
+	CallValue   *big.Int
+	TokenValues map[common.Address]*big.Int
+	Result      *Result
+}
+
+// Stage is one check in a ValidatorChain. Returning a ValidationError rejects
+// the request, any other error defers it to a later poll.
+type Stage interface {
+	Validate(ctx context.Context, req *Request) error
+}
+
+type StageFunc func(ctx context.Context, req *Request) error
+
+func (f StageFunc) Validate(ctx context.Context, req *Request) error {
+	return f(ctx, req)
+}
+
+type stage struct {
+	phase Phase
+	name  string
+	Stage
+}
+
+// ValidatorChain runs its stages in order and stops at the first one that
+// fails.
+type ValidatorChain struct {
+	srcChain *chains.ChainConfig
+	stages   []stage
+}
+
+func NewValidatorChain(srcChain *chains.ChainConfig) *ValidatorChain {
+	return &ValidatorChain{srcChain: srcChain}
+}
+
+// Use adds a stage to the chain.
+func (c *ValidatorChain) Use(phase Phase, name string, s Stage) *ValidatorChain {
+	c.stages = append(c.stages, stage{phase: phase, name: name, Stage: s})
+	sort.SliceStable(c.stages, func(i, j int) bool { return c.stages[i].phase < c.stages[j].phase })
+	return c
+}
+
+func (c *ValidatorChain) ValidateLog(log *bindings.RIP7755OutboxCrossChainCallRequested) (*Result, error) {
+	logger.Info("Validating log")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	req := &Request{Log: log, SrcChain: c.srcChain, Result: &Result{}}
+	for _, s := range c.stages {
+		if err := s.Validate(ctx, req); err != nil {
+			logger.Info("Request failed validation", "phase", s.phase, "stage", s.name, "error", err)
+			return nil, err
+		}
+	}
+
+	return req.Result, nil
+}
+
+// Current validates logs with a chain that can be replaced at any time, e.g.
+// when the networks config is reloaded. A log is validated entirely by the
+// chain that was current when its validation started.
+type Current struct {
+	chain atomic.Pointer[ValidatorChain]
+}
+
+func NewCurrent(c *ValidatorChain) *Current {
+	current := &Current{}
+	current.chain.Store(c)
+	return current
+}
+
+// Store replaces the chain used for logs validated from now on.
+func (c *Current) Store(chain *ValidatorChain) {
+	c.chain.Store(chain)
+}
+
+func (c *Current) ValidateLog(log *bindings.RIP7755OutboxCrossChainCallRequested) (*Result, error) {
+	return c.chain.Load().ValidateLog(log)
+}
+
+var (
+	registryMu sync.Mutex
+	registry   []stage
+)
+
+// Register adds a stage to every chain built by NewValidator afterwards. It is
+// meant for teams embedding the filler to plug in their own checks, typically
+// from an init function.
+func Register(phase Phase, name string, s Stage) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	registry = append(registry, stage{phase: phase, name: name, Stage: s})
+}
+
+func registered() []stage {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	return append([]stage(nil), registry...)
+}
@@ -0,0 +1,113 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/clients"
+	"github.com/stretchr/testify/assert"
+)
+
+func recordStage(name string, calls *[]string, err error) Stage {
+	return StageFunc(func(ctx context.Context, req *Request) error {
+		*calls = append(*calls, name)
+		return err
+	})
+}
+
+func TestValidatorChainRunsStagesInPhaseOrder(t *testing.T) {
+	var calls []string
+	chain := NewValidatorChain(srcChain).
+		Use(Custom, "custom", recordStage("custom", &calls, nil)).
+		Use(Simulation, "simulation", recordStage("simulation", &calls, nil)).
+		Use(Structural, "structural", recordStage("structural", &calls, nil)).
+		Use(Economic, "economic", recordStage("economic", &calls, nil)).
+		Use(Route, "route", recordStage("route", &calls, nil)).
+		Use(Structural, "structural-2", recordStage("structural-2", &calls, nil))
+
+	result, err := chain.ValidateLog(parsedLog)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, []string{"structural", "structural-2", "route", "economic", "simulation", "custom"}, calls)
+}
+
+func TestValidatorChainShortCircuits(t *testing.T) {
+	var calls []string
+	chain := NewValidatorChain(srcChain).
+		Use(Structural, "first", recordStage("first", &calls, ErrExpired)).
+		Use(Route, "second", recordStage("second", &calls, nil))
+
+	_, err := chain.ValidateLog(parsedLog)
+
+	assert.ErrorIs(t, err, ErrExpired)
+	assert.Equal(t, []string{"first"}, calls)
+}
+
+func TestValidatorChainCarriesContext(t *testing.T) {
+	chain := NewValidatorChain(srcChain).
+		Use(Economic, "estimate", StageFunc(func(ctx context.Context, req *Request) error {
+			assert.Equal(t, parsedLog, req.Log)
+			assert.Equal(t, srcChain, req.SrcChain)
+			req.Result.RewardValue = big.NewInt(42)
+			return nil
+		})).
+		Use(Custom, "check", StageFunc(func(ctx context.Context, req *Request) error {
+			if req.Result.RewardValue.Cmp(big.NewInt(42)) != 0 {
+				return errors.New("missing reward value")
+			}
+			return nil
+		}))
+
+	result, err := chain.ValidateLog(parsedLog)
+
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(42), result.RewardValue)
+}
+
+func TestCurrentSwapsChain(t *testing.T) {
+	var calls []string
+	current := NewCurrent(NewValidatorChain(srcChain).Use(Structural, "old", recordStage("old", &calls, nil)))
+
+	_, err := current.ValidateLog(parsedLog)
+	assert.NoError(t, err)
+
+	current.Store(NewValidatorChain(srcChain).Use(Structural, "new", recordStage("new", &calls, ErrExpired)))
+	_, err = current.ValidateLog(parsedLog)
+
+	assert.ErrorIs(t, err, ErrExpired)
+	assert.Equal(t, []string{"old", "new"}, calls)
+}
+
+func TestNewValidatorIncludesRegisteredStages(t *testing.T) {
+	defer func() { registry = nil }()
+
+	rejected := &ValidationError{Code: "custom_rejected"}
+	Register(Custom, "reject-all", StageFunc(func(ctx context.Context, req *Request) error {
+		return rejected
+	}))
+
+	chain := NewValidator(srcChain, networksCfg.Networks, oracle, nil, nil, clients.NewPool())
+
+	assert.Equal(t, "reject-all", chain.stages[len(chain.stages)-1].name)
+	assert.Equal(t, Custom, chain.stages[len(chain.stages)-1].phase)
+}
+
+func TestBuiltInStageOrder(t *testing.T) {
+	expected := []string{"requester", "request", "contracts", "calls", "timing", "status", "profitability", "policy", "precheck", "fulfill"}
+
+	for name, chain := range map[string]*ValidatorChain{
+		"NewValidator": NewValidator(srcChain, networksCfg.Networks, oracle, nil, nil, clients.NewPool()),
+		"newValidator": newTestValidator(),
+	} {
+		var names []string
+		for _, s := range chain.stages {
+			names = append(names, s.name)
+		}
+		assert.Equal(t, expected, names, name)
+		// settled requests are only looked up once the route checks passed
+		assert.Equal(t, Route, chain.stages[5].phase, name)
+	}
+}
@@ -0,0 +1,135 @@
+package validator
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/policy"
+)
+
+// ReasonCode identifies why a request was rejected. Codes are stable and safe
+// to persist or use as metric labels.
+type ReasonCode string
+
+const (
+	ReasonUnknownDestinationChain ReasonCode = "unknown_destination_chain"
+	ReasonMissingProverName       ReasonCode = "missing_prover_name"
+	ReasonProverNotConfigured     ReasonCode = "prover_not_configured"
+	ReasonUnknownProver           ReasonCode = "unknown_prover"
+	ReasonUnknownInbox            ReasonCode = "unknown_inbox"
+	ReasonUnknownL2Oracle         ReasonCode = "unknown_l2_oracle"
+	ReasonUnknownL2OracleKey      ReasonCode = "unknown_l2_oracle_storage_key"
+	ReasonUnsupportedRewardAsset  ReasonCode = "unsupported_reward_asset"
+	ReasonInsufficientReward      ReasonCode = "insufficient_reward"
+	ReasonUnprofitable            ReasonCode = "unprofitable"
+	ReasonMissingDelay            ReasonCode = "missing_delay"
+	ReasonExpired                 ReasonCode = "expired"
+	ReasonInsufficientTime        ReasonCode = "insufficient_time"
+	ReasonPrecheckFailed          ReasonCode = "precheck_failed"
+	ReasonSimulationReverted      ReasonCode = "simulation_reverted"
+	ReasonTooManyCalls            ReasonCode = "too_many_calls"
+	ReasonCallValueTooHigh        ReasonCode = "call_value_too_high"
+	ReasonCallNotPermitted        ReasonCode = "call_not_permitted"
+	ReasonValueOutOfRange         ReasonCode = "value_out_of_range"
+	ReasonRequesterBlocked        ReasonCode = "requester_blocked"
+	ReasonRateLimited             ReasonCode = "rate_limited"
+	ReasonMissingShoyuBashi       ReasonCode = "missing_shoyu_bashi"
+	ReasonUnknownShoyuBashi       ReasonCode = "unknown_shoyu_bashi"
+	ReasonRequestCanceled         ReasonCode = "request_canceled"
+	ReasonRequestCompleted        ReasonCode = "request_completed"
+	ReasonAlreadyFulfilled        ReasonCode = "already_fulfilled"
+	ReasonPolicyNoRoute           ReasonCode = "policy_no_route"
+	ReasonPolicyProver            ReasonCode = "policy_prover"
+	ReasonPolicyMaxValue          ReasonCode = "policy_max_value"
+	ReasonPolicyToken             ReasonCode = "policy_token"
+	ReasonPolicyMissingAttribute  ReasonCode = "policy_missing_attribute"
+	ReasonPolicyMargin            ReasonCode = "policy_margin"
+)
+
+var (
+	ErrUnknownDestinationChain = &ValidationError{Code: ReasonUnknownDestinationChain}
+	ErrMissingProverName       = &ValidationError{Code: ReasonMissingProverName}
+	ErrProverNotConfigured     = &ValidationError{Code: ReasonProverNotConfigured}
+	ErrUnknownProver           = &ValidationError{Code: ReasonUnknownProver}
+	ErrUnknownInbox            = &ValidationError{Code: ReasonUnknownInbox}
+	ErrUnknownL2Oracle         = &ValidationError{Code: ReasonUnknownL2Oracle}
+	ErrUnknownL2OracleKey      = &ValidationError{Code: ReasonUnknownL2OracleKey}
+	ErrUnsupportedRewardAsset  = &ValidationError{Code: ReasonUnsupportedRewardAsset}
+	ErrInsufficientReward      = &ValidationError{Code: ReasonInsufficientReward}
+	ErrUnprofitable            = &ValidationError{Code: ReasonUnprofitable}
+	ErrMissingDelay            = &ValidationError{Code: ReasonMissingDelay}
+	ErrExpired                 = &ValidationError{Code: ReasonExpired}
+	ErrInsufficientTime        = &ValidationError{Code: ReasonInsufficientTime}
+	ErrPrecheckFailed          = &ValidationError{Code: ReasonPrecheckFailed}
+	ErrSimulationReverted      = &ValidationError{Code: ReasonSimulationReverted}
+	ErrTooManyCalls            = &ValidationError{Code: ReasonTooManyCalls}
+	ErrCallValueTooHigh        = &ValidationError{Code: ReasonCallValueTooHigh}
+	ErrCallNotPermitted        = &ValidationError{Code: ReasonCallNotPermitted}
+	ErrValueOutOfRange         = &ValidationError{Code: ReasonValueOutOfRange}
+	ErrRequesterBlocked        = &ValidationError{Code: ReasonRequesterBlocked}
+	ErrRateLimited             = &ValidationError{Code: ReasonRateLimited}
+	ErrMissingShoyuBashi       = &ValidationError{Code: ReasonMissingShoyuBashi}
+	ErrUnknownShoyuBashi       = &ValidationError{Code: ReasonUnknownShoyuBashi}
+	ErrRequestCanceled         = &ValidationError{Code: ReasonRequestCanceled}
+	ErrRequestCompleted        = &ValidationError{Code: ReasonRequestCompleted}
+	ErrAlreadyFulfilled        = &ValidationError{Code: ReasonAlreadyFulfilled}
+	ErrPolicyNoRoute           = &ValidationError{Code: ReasonPolicyNoRoute}
+	ErrPolicyProver            = &ValidationError{Code: ReasonPolicyProver}
+	ErrPolicyMaxValue          = &ValidationError{Code: ReasonPolicyMaxValue}
+	ErrPolicyToken             = &ValidationError{Code: ReasonPolicyToken}
+	ErrPolicyMissingAttribute  = &ValidationError{Code: ReasonPolicyMissingAttribute}
+	ErrPolicyMargin            = &ValidationError{Code: ReasonPolicyMargin}
+)
+
+// ValidationError is returned by ValidateLog when a request is rejected.
+// Expected and Actual hold the configured and requested values for routing
+// checks, Required and Offered hold the amounts for reward checks and the
+// timestamps for expiry checks.
+type ValidationError struct {
+	Code     ReasonCode
+	Expected string
+	Actual   string
+	Required *big.Int
+	Offered  *big.Int
+}
+
+func (e *ValidationError) Error() string {
+	switch {
+	case e.Required != nil || e.Offered != nil:
+		return fmt.Sprintf("%s: required %s, offered %s", e.Code, e.Required, e.Offered)
+	case e.Expected != "" && e.Actual != "":
+		return fmt.Sprintf("%s: expected %s, actual %s", e.Code, e.Expected, e.Actual)
+	case e.Expected != "":
+		return fmt.Sprintf("%s: expected %s", e.Code, e.Expected)
+	case e.Actual != "":
+		return fmt.Sprintf("%s: %s", e.Code, e.Actual)
+	default:
+		return string(e.Code)
+	}
+}
+
+// Is reports whether target is a ValidationError with the same reason code, so
+// that errors.Is(err, ErrUnknownProver) works regardless of the detail fields.
+func (e *ValidationError) Is(target error) bool {
+	t, ok := target.(*ValidationError)
+	return ok && t.Code == e.Code
+}
+
+func mismatch(code ReasonCode, expected, actual fmt.Stringer) *ValidationError {
+	return &ValidationError{Code: code, Expected: expected.String(), Actual: actual.String()}
+}
+
+var policyReasons = map[policy.Code]ReasonCode{
+	policy.CodeNoRoute:          ReasonPolicyNoRoute,
+	policy.CodeProver:           ReasonPolicyProver,
+	policy.CodeMaxValue:         ReasonPolicyMaxValue,
+	policy.CodeToken:            ReasonPolicyToken,
+	policy.CodeMissingAttribute: ReasonPolicyMissingAttribute,
+	policy.CodeMargin:           ReasonPolicyMargin,
+}
+
+// policyViolation reports a route policy violation, with the route name as
+// Expected.
+func policyViolation(v *policy.Violation) *ValidationError {
+	return &ValidationError{Code: policyReasons[v.Code], Expected: v.Route, Actual: v.Actual, Required: v.Required, Offered: v.Offered}
+}
@@ -0,0 +1,45 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidationErrorIsMatchesByCode(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", mismatch(ReasonUnknownProver, common.HexToAddress("0x1"), common.HexToAddress("0x2")))
+
+	assert.ErrorIs(t, err, ErrUnknownProver)
+	assert.NotErrorIs(t, err, ErrUnknownInbox)
+}
+
+func TestValidationErrorAsExposesFields(t *testing.T) {
+	err := error(&ValidationError{Code: ReasonInsufficientReward, Required: big.NewInt(2), Offered: big.NewInt(1)})
+
+	var vErr *ValidationError
+	assert.True(t, errors.As(err, &vErr))
+	assert.Equal(t, ReasonInsufficientReward, vErr.Code)
+	assert.Equal(t, big.NewInt(2), vErr.Required)
+	assert.Equal(t, big.NewInt(1), vErr.Offered)
+}
+
+func TestValidationErrorMessage(t *testing.T) {
+	testCases := []struct {
+		err      *ValidationError
+		expected string
+	}{
+		{ErrMissingProverName, "missing_prover_name"},
+		{&ValidationError{Code: ReasonUnknownDestinationChain, Actual: "1"}, "unknown_destination_chain: 1"},
+		{&ValidationError{Code: ReasonProverNotConfigured, Expected: "OPStack"}, "prover_not_configured: expected OPStack"},
+		{&ValidationError{Code: ReasonUnknownInbox, Expected: "0xa", Actual: "0xb"}, "unknown_inbox: expected 0xa, actual 0xb"},
+		{&ValidationError{Code: ReasonInsufficientReward, Required: big.NewInt(2), Offered: big.NewInt(1)}, "insufficient_reward: required 2, offered 1"},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, tc.err.Error())
+	}
+}
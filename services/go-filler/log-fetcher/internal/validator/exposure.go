@@ -0,0 +1,298 @@
+package validator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/base-org/RIP-7755-poc/services/go-filler/bindings"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/chains"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/clients"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/ids"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/metrics"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/store"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrExposureExceeded defers a request that would commit more of the
+// fulfiller's capital than the destination chain allows. It is not a
+// ValidationError, so the request is retried on a later poll once pending jobs
+// have been fulfilled.
+var ErrExposureExceeded = errors.New("exposure exceeded")
+
+// reservationTTL is how long a request that passed the exposure check counts
+// against its destination before it shows up as a pending job. Requests
+// rejected by a later stage hold their reservation until then.
+const reservationTTL = time.Minute
+
+var (
+	erc20Transfer  = newERC20Method("transfer", []string{"address", "uint256"}, "bool")
+	erc20BalanceOf = newERC20Method("balanceOf", []string{"address"}, "uint256")
+)
+
+// Commitments reports what accepted requests that haven't been fulfilled yet
+// commit on a destination chain.
+type Commitments interface {
+	Pending(dstChainId string) ([]store.Commitment, error)
+}
+
+// BalanceClient reads the fulfiller's native and ERC-20 balances on an EVM
+// chain.
+type BalanceClient interface {
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+	CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+}
+
+// SolanaBalanceClient reads lamport balances on a Solana chain.
+type SolanaBalanceClient interface {
+	Balance(ctx context.Context, account ids.Pubkey) (uint64, error)
+}
+
+type exposure struct {
+	commitments Commitments
+	dial        func(*chains.ChainConfig) (BalanceClient, error)
+	dialSolana  func(*chains.ChainConfig) (SolanaBalanceClient, error)
+	now         func() time.Time
+
+	// reserved holds the requests that passed the check but may not be
+	// pending jobs yet, by destination chain and request hash. Checks are
+	// serialized so that two requests can't both pass on the same capital.
+	mu       sync.Mutex
+	reserved map[string]map[[32]byte]reservation
+}
+
+type reservation struct {
+	store.Commitment
+	expires time.Time
+}
+
+// NewExposureCheck returns a stage that holds requests to their destination
+// chain's exposure caps and to the fulfiller's balances there, read through
+// pool's clients. The stage keeps track of the requests it let through, so
+// it should be shared by the validators of all source chains.
+func NewExposureCheck(commitments Commitments, pool *clients.Pool) Stage {
+	return newExposureCheck(commitments, func(cfg *chains.ChainConfig) (BalanceClient, error) {
+		return pool.Eth(cfg)
+	}, func(cfg *chains.ChainConfig) (SolanaBalanceClient, error) {
+		return pool.Solana(cfg)
+	})
+}
+
+func newExposureCheck(commitments Commitments, dial func(*chains.ChainConfig) (BalanceClient, error), dialSolana func(*chains.ChainConfig) (SolanaBalanceClient, error)) *exposure {
+	return &exposure{
+		commitments: commitments,
+		dial:        dial,
+		dialSolana:  dialSolana,
+		now:         time.Now,
+		reserved:    make(map[string]map[[32]byte]reservation),
+	}
+}
+
+func (e *exposure) Validate(ctx context.Context, req *Request) error {
+	dstChain, limits := req.DstChain, req.DstChain.Exposure
+	dstChainId := req.Log.Request.DestinationChainId.String()
+
+	if limits.MaxRequestValue != nil && req.CallValue.Cmp(limits.MaxRequestValue) > 0 {
+		return deferred("request value %s above cap %s on chain %s", req.CallValue, limits.MaxRequestValue, dstChainId)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	pending, err := e.commitments.Pending(dstChainId)
+	if err != nil {
+		return fmt.Errorf("failed to read outstanding value: %v", err)
+	}
+	request := store.Commitment{RequestHash: req.Log.RequestHash, CallValue: req.CallValue, TokenValues: req.TokenValues}
+	committed, committedTokens := e.total(dstChainId, pending, request)
+
+	if limits.MaxOutstanding != nil && committed.Cmp(limits.MaxOutstanding) > 0 {
+		return deferred("outstanding value %s above cap %s on chain %s", committed, limits.MaxOutstanding, dstChainId)
+	}
+
+	balance, err := e.balance(ctx, dstChain)
+	if err != nil {
+		return err
+	}
+
+	needed := new(big.Int).Set(committed)
+	if limits.MinBalance != nil {
+		needed.Add(needed, limits.MinBalance)
+	}
+	if balance.Cmp(needed) < 0 {
+		return deferred("fulfiller balance %s below %s needed on chain %s", balance, needed, dstChainId)
+	}
+
+	for token := range req.TokenValues {
+		balance, err := e.tokenBalance(ctx, dstChain, token)
+		if err != nil {
+			return err
+		}
+		if balance.Cmp(committedTokens[token]) < 0 {
+			return deferred("fulfiller balance %s of token %s below %s needed on chain %s", balance, token.Hex(), committedTokens[token], dstChainId)
+		}
+	}
+
+	e.reserve(dstChainId, request)
+	return nil
+}
+
+// total adds up the request with the pending jobs and with the reservations
+// of requests that aren't pending jobs yet. Reservations that show up among
+// the pending jobs, or that expired, are dropped.
+func (e *exposure) total(dstChainId string, pending []store.Commitment, request store.Commitment) (*big.Int, map[common.Address]*big.Int) {
+	reserved := e.reserved[dstChainId]
+	commitments := []store.Commitment{request}
+	for _, c := range pending {
+		delete(reserved, c.RequestHash)
+		if c.RequestHash != request.RequestHash {
+			commitments = append(commitments, c)
+		}
+	}
+	for hash, r := range reserved {
+		switch {
+		case e.now().After(r.expires):
+			delete(reserved, hash)
+		case hash != request.RequestHash:
+			commitments = append(commitments, r.Commitment)
+		}
+	}
+
+	value, tokens := new(big.Int), map[common.Address]*big.Int{}
+	for _, c := range commitments {
+		value.Add(value, c.CallValue)
+		for token, amount := range c.TokenValues {
+			if tokens[token] == nil {
+				tokens[token] = new(big.Int)
+			}
+			tokens[token].Add(tokens[token], amount)
+		}
+	}
+
+	return value, tokens
+}
+
+func (e *exposure) reserve(dstChainId string, request store.Commitment) {
+	if e.reserved[dstChainId] == nil {
+		e.reserved[dstChainId] = make(map[[32]byte]reservation)
+	}
+	e.reserved[dstChainId][request.RequestHash] = reservation{Commitment: request, expires: e.now().Add(reservationTTL)}
+}
+
+// balance returns the fulfiller's native balance on the destination chain, in
+// lamports on Solana.
+func (e *exposure) balance(ctx context.Context, dstChain *chains.ChainConfig) (*big.Int, error) {
+	dstChainId := dstChain.ChainId.String()
+
+	if dstChain.ChainKind() == chains.KindSolana {
+		if dstChain.Solana == nil || dstChain.Solana.Fulfiller.IsZero() {
+			return nil, fmt.Errorf("no fulfiller configured for destination chain %s", dstChainId)
+		}
+
+		client, err := e.dialSolana(dstChain)
+		if err != nil {
+			return nil, err
+		}
+
+		lamports, err := client.Balance(ctx, dstChain.Solana.Fulfiller)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get fulfiller balance on chain %s: %v", dstChainId, err)
+		}
+		return new(big.Int).SetUint64(lamports), nil
+	}
+
+	if dstChain.Fulfiller == (common.Address{}) {
+		return nil, fmt.Errorf("no fulfiller configured for destination chain %s", dstChainId)
+	}
+
+	client, err := e.dial(dstChain)
+	if err != nil {
+		return nil, err
+	}
+
+	balance, err := client.BalanceAt(ctx, dstChain.Fulfiller, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fulfiller balance on chain %s: %v", dstChainId, err)
+	}
+	return balance, nil
+}
+
+// tokenBalance returns the fulfiller's balance of an ERC-20 token on an EVM
+// destination chain.
+func (e *exposure) tokenBalance(ctx context.Context, dstChain *chains.ChainConfig, token common.Address) (*big.Int, error) {
+	client, err := e.dial(dstChain)
+	if err != nil {
+		return nil, err
+	}
+
+	input, err := erc20BalanceOf.Inputs.Pack(dstChain.Fulfiller)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := client.CallContract(ctx, ethereum.CallMsg{To: &token, Data: append(erc20BalanceOf.ID, input...)}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fulfiller balance of token %s on chain %s: %v", token.Hex(), dstChain.ChainId, err)
+	}
+
+	values, err := erc20BalanceOf.Outputs.Unpack(out)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode balance of token %s: %v", token.Hex(), err)
+	}
+
+	return values[0].(*big.Int), nil
+}
+
+// tokenValues adds up the amounts the calls transfer of the ERC-20 tokens
+// configured on the destination chain. The fulfiller funds these along with
+// the call value.
+func tokenValues(dstChain *chains.ChainConfig, calls []bindings.Call) map[common.Address]*big.Int {
+	var values map[common.Address]*big.Int
+	for _, call := range calls {
+		token := common.BytesToAddress(call.To[:])
+		if _, ok := dstChain.GetRewardToken(token); !ok {
+			continue
+		}
+		if len(call.Data) < 4 || !bytes.Equal(call.Data[:4], erc20Transfer.ID) {
+			continue
+		}
+
+		args, err := erc20Transfer.Inputs.Unpack(call.Data[4:])
+		if err != nil {
+			continue
+		}
+
+		if values == nil {
+			values = make(map[common.Address]*big.Int)
+		}
+		if values[token] == nil {
+			values[token] = new(big.Int)
+		}
+		values[token].Add(values[token], args[1].(*big.Int))
+	}
+
+	return values
+}
+
+func newERC20Method(name string, inputTypes []string, outputType string) abi.Method {
+	var inputs abi.Arguments
+	for _, t := range inputTypes {
+		typ, _ := abi.NewType(t, "", nil)
+		inputs = append(inputs, abi.Argument{Type: typ})
+	}
+	typ, _ := abi.NewType(outputType, "", nil)
+	outputs := abi.Arguments{{Type: typ}}
+
+	return abi.NewMethod(name, name, abi.Function, "", false, false, inputs, outputs)
+}
+
+func deferred(format string, args ...interface{}) error {
+	metrics.Counter("validator/deferred/exposure").Inc(1)
+	return fmt.Errorf("%w: "+format, append([]interface{}{ErrExposureExceeded}, args...)...)
+}
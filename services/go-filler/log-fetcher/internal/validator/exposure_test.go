@@ -0,0 +1,222 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/base-org/RIP-7755-poc/services/go-filler/bindings"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/chains"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/ids"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/store"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type CommitmentsMock struct {
+	mock.Mock
+}
+
+func (c *CommitmentsMock) Pending(dstChainId string) ([]store.Commitment, error) {
+	args := c.Called(dstChainId)
+	return args.Get(0).([]store.Commitment), args.Error(1)
+}
+
+type BalanceClientMock struct {
+	mock.Mock
+}
+
+func (b *BalanceClientMock) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	args := b.Called(account)
+	return args.Get(0).(*big.Int), args.Error(1)
+}
+
+func (b *BalanceClientMock) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	args := b.Called(*msg.To, msg.Data)
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+type SolanaBalanceClientMock struct {
+	mock.Mock
+}
+
+func (b *SolanaBalanceClientMock) Balance(ctx context.Context, account ids.Pubkey) (uint64, error) {
+	args := b.Called(account)
+	return args.Get(0).(uint64), args.Error(1)
+}
+
+var fulfiller = common.HexToAddress("0x8888888888888888888888888888888888888888")
+
+// pendingJob is a job committing value that is pending on chain 84532.
+func pendingJob(value int64) []store.Commitment {
+	return []store.Commitment{{RequestHash: [32]byte{0xaa}, CallValue: big.NewInt(value)}}
+}
+
+func newExposureTest(limits chains.ExposureConfig, outstanding, balance int64) (*exposure, *Request, *BalanceClientMock) {
+	commitments := new(CommitmentsMock)
+	commitments.On("Pending", "84532").Return(pendingJob(outstanding), nil)
+	client := new(BalanceClientMock)
+	client.On("BalanceAt", fulfiller).Return(big.NewInt(balance), nil)
+
+	check := newExposureCheck(commitments, func(*chains.ChainConfig) (BalanceClient, error) {
+		return client, nil
+	}, nil)
+	dst := &chains.ChainConfig{ChainId: big.NewInt(84532), Fulfiller: fulfiller, Exposure: limits}
+	req := &Request{Log: parsedLog, DstChain: dst, CallValue: big.NewInt(100)}
+
+	return check, req, client
+}
+
+func TestExposureAccepts(t *testing.T) {
+	check, req, _ := newExposureTest(chains.ExposureConfig{MaxOutstanding: big.NewInt(300), MaxRequestValue: big.NewInt(100), MinBalance: big.NewInt(50)}, 200, 350)
+
+	assert.NoError(t, check.Validate(context.Background(), req))
+}
+
+func TestExposureDefersRequestAboveCap(t *testing.T) {
+	check, req, client := newExposureTest(chains.ExposureConfig{MaxRequestValue: big.NewInt(99)}, 0, 1_000)
+
+	err := check.Validate(context.Background(), req)
+
+	assert.ErrorIs(t, err, ErrExposureExceeded)
+	assert.False(t, errors.As(err, new(*ValidationError)))
+	client.AssertNotCalled(t, "BalanceAt", mock.Anything)
+}
+
+func TestExposureDefersOutstandingAboveCap(t *testing.T) {
+	check, req, _ := newExposureTest(chains.ExposureConfig{MaxOutstanding: big.NewInt(299)}, 200, 1_000)
+
+	assert.ErrorIs(t, check.Validate(context.Background(), req), ErrExposureExceeded)
+}
+
+func TestExposureDefersInsufficientBalance(t *testing.T) {
+	check, req, _ := newExposureTest(chains.ExposureConfig{MinBalance: big.NewInt(50)}, 200, 349)
+
+	assert.ErrorIs(t, check.Validate(context.Background(), req), ErrExposureExceeded)
+}
+
+func TestExposureReturnsStoreError(t *testing.T) {
+	commitments := new(CommitmentsMock)
+	commitments.On("Pending", "84532").Return([]store.Commitment(nil), errors.New("test error"))
+	check := newExposureCheck(commitments, nil, nil)
+	req := &Request{Log: parsedLog, DstChain: &chains.ChainConfig{ChainId: big.NewInt(84532)}, CallValue: big.NewInt(100)}
+
+	err := check.Validate(context.Background(), req)
+
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrExposureExceeded)
+}
+
+func TestExposureSolana(t *testing.T) {
+	solanaFulfiller, _ := ids.ParsePubkey("4rPLqoMbtPAMdYeytQagQyt5ucVxRJpx7BjL2jW49UsQ")
+	commitments := new(CommitmentsMock)
+	commitments.On("Pending", "84532").Return(pendingJob(200), nil)
+	client := new(SolanaBalanceClientMock)
+	client.On("Balance", solanaFulfiller).Return(uint64(299), nil)
+
+	check := newExposureCheck(commitments, nil, func(*chains.ChainConfig) (SolanaBalanceClient, error) {
+		return client, nil
+	})
+	dst := &chains.ChainConfig{Kind: chains.KindSolana, ChainId: big.NewInt(103), Solana: &chains.SolanaConfig{Fulfiller: solanaFulfiller}}
+	req := &Request{Log: parsedLog, DstChain: dst, CallValue: big.NewInt(100)}
+
+	assert.ErrorIs(t, check.Validate(context.Background(), req), ErrExposureExceeded)
+	client.AssertExpectations(t)
+}
+
+func TestExposureReservesAcceptedRequests(t *testing.T) {
+	commitments := new(CommitmentsMock)
+	commitments.On("Pending", "84532").Return([]store.Commitment(nil), nil).Times(3)
+	client := new(BalanceClientMock)
+	client.On("BalanceAt", fulfiller).Return(big.NewInt(1_000), nil)
+	check := newExposureCheck(commitments, func(*chains.ChainConfig) (BalanceClient, error) {
+		return client, nil
+	}, nil)
+	now := time.Now()
+	check.now = func() time.Time { return now }
+	dst := &chains.ChainConfig{ChainId: big.NewInt(84532), Fulfiller: fulfiller, Exposure: chains.ExposureConfig{MaxOutstanding: big.NewInt(150)}}
+	second := *parsedLog
+	second.RequestHash = [32]byte{0x02}
+
+	assert.NoError(t, check.Validate(context.Background(), &Request{Log: parsedLog, DstChain: dst, CallValue: big.NewInt(100)}))
+	// the first request isn't queued yet, but its value is reserved
+	assert.ErrorIs(t, check.Validate(context.Background(), &Request{Log: &second, DstChain: dst, CallValue: big.NewInt(100)}), ErrExposureExceeded)
+	// checking the first request again doesn't count it twice
+	assert.NoError(t, check.Validate(context.Background(), &Request{Log: parsedLog, DstChain: dst, CallValue: big.NewInt(100)}))
+
+	// once queued, the job counts instead of the reservation
+	commitments.On("Pending", "84532").Return([]store.Commitment{{RequestHash: parsedLog.RequestHash, CallValue: big.NewInt(100)}}, nil).Once()
+	assert.ErrorIs(t, check.Validate(context.Background(), &Request{Log: &second, DstChain: dst, CallValue: big.NewInt(100)}), ErrExposureExceeded)
+	assert.Empty(t, check.reserved["84532"])
+}
+
+func TestExposureReservationExpires(t *testing.T) {
+	commitments := new(CommitmentsMock)
+	commitments.On("Pending", "84532").Return([]store.Commitment(nil), nil)
+	client := new(BalanceClientMock)
+	client.On("BalanceAt", fulfiller).Return(big.NewInt(1_000), nil)
+	check := newExposureCheck(commitments, func(*chains.ChainConfig) (BalanceClient, error) {
+		return client, nil
+	}, nil)
+	now := time.Now()
+	check.now = func() time.Time { return now }
+	dst := &chains.ChainConfig{ChainId: big.NewInt(84532), Fulfiller: fulfiller, Exposure: chains.ExposureConfig{MaxOutstanding: big.NewInt(150)}}
+	second := *parsedLog
+	second.RequestHash = [32]byte{0x02}
+
+	assert.NoError(t, check.Validate(context.Background(), &Request{Log: parsedLog, DstChain: dst, CallValue: big.NewInt(100)}))
+
+	// the first request was rejected by a later stage and never queued
+	now = now.Add(reservationTTL + time.Second)
+	assert.NoError(t, check.Validate(context.Background(), &Request{Log: &second, DstChain: dst, CallValue: big.NewInt(100)}))
+}
+
+func TestExposureChecksTokenBalance(t *testing.T) {
+	usdc := common.HexToAddress("0x036CbD53842c5426634e7929541eC2318f3dCF7e")
+	commitments := new(CommitmentsMock)
+	commitments.On("Pending", "84532").Return([]store.Commitment{
+		{RequestHash: [32]byte{0xaa}, CallValue: big.NewInt(0), TokenValues: map[common.Address]*big.Int{usdc: big.NewInt(1_000_000)}},
+	}, nil)
+	balanceOf, _ := erc20BalanceOf.Inputs.Pack(fulfiller)
+	balance, _ := erc20BalanceOf.Outputs.Pack(big.NewInt(5_500_000))
+	client := new(BalanceClientMock)
+	client.On("BalanceAt", fulfiller).Return(big.NewInt(1_000), nil)
+	client.On("CallContract", usdc, append(erc20BalanceOf.ID, balanceOf...)).Return(balance, nil)
+	check := newExposureCheck(commitments, func(*chains.ChainConfig) (BalanceClient, error) {
+		return client, nil
+	}, nil)
+	dst := &chains.ChainConfig{ChainId: big.NewInt(84532), Fulfiller: fulfiller}
+	req := &Request{Log: parsedLog, DstChain: dst, CallValue: big.NewInt(0), TokenValues: map[common.Address]*big.Int{usdc: big.NewInt(5_000_000)}}
+
+	err := check.Validate(context.Background(), req)
+
+	assert.ErrorIs(t, err, ErrExposureExceeded)
+	assert.ErrorContains(t, err, "below 6000000")
+
+	req.TokenValues[usdc] = big.NewInt(4_500_000)
+	assert.NoError(t, check.Validate(context.Background(), req))
+}
+
+func TestTokenValues(t *testing.T) {
+	usdc := common.HexToAddress("0x036CbD53842c5426634e7929541eC2318f3dCF7e")
+	other := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	dst := &chains.ChainConfig{RewardTokens: []chains.TokenConfig{{Symbol: "USDC", Address: usdc, Decimals: 6}}}
+	transfer := func(amount int64) []byte {
+		input, _ := erc20Transfer.Inputs.Pack(other, big.NewInt(amount))
+		return append(erc20Transfer.ID, input...)
+	}
+
+	values := tokenValues(dst, []bindings.Call{
+		{To: usdc, Data: transfer(1_000_000), Value: big.NewInt(0)},
+		{To: usdc, Data: transfer(500_000), Value: big.NewInt(0)},
+		{To: other, Data: transfer(7), Value: big.NewInt(0)},
+		{To: usdc, Data: []byte{0x01, 0x02}, Value: big.NewInt(0)},
+	})
+
+	assert.Equal(t, map[common.Address]*big.Int{usdc: big.NewInt(1_500_000)}, values)
+	assert.Nil(t, tokenValues(dst, []bindings.Call{{To: other, Data: transfer(7), Value: big.NewInt(0)}}))
+}
@@ -0,0 +1,87 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/base-org/RIP-7755-poc/services/go-filler/bindings"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/attributes"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/clients"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/ids"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// precheckCall is IPrecheckContract.precheckCall(CrossChainRequest, address),
+// which the Inbox calls during fulfill with the fulfiller as caller.
+var precheckCall = mustPrecheckMethod()
+
+// validatePrecheck runs the request's precheck contract against the latest
+// destination state, the same way the Inbox would when we fulfill. A revert
+// rejects the request, any other failure is returned as a plain error so the
+// request is picked up again later.
+func (v *validator) validatePrecheck(ctx context.Context, req *Request) error {
+	request, dstChain := &req.Log.Request, req.DstChain
+	if isSolana(dstChain) {
+		return nil
+	}
+
+	precheck, err := req.Attributes.Precheck()
+	if errors.Is(err, attributes.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	precheckAddr, ok := ids.Bytes32ToAddress(precheck)
+	if !ok {
+		return &ValidationError{Code: ReasonPrecheckFailed, Actual: common.Hash(precheck).Hex()}
+	}
+
+	if dstChain.Fulfiller == (common.Address{}) {
+		return fmt.Errorf("no fulfiller configured for destination chain %s", dstChain.ChainId)
+	}
+
+	input, err := precheckCall.Inputs.Pack(*request, dstChain.Fulfiller)
+	if err != nil {
+		return fmt.Errorf("failed to encode precheck call: %v", err)
+	}
+
+	client, err := v.dial(dstChain)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.CallContract(ctx, ethereum.CallMsg{
+		From: dstChain.Contracts.Inbox,
+		To:   &precheckAddr,
+		Data: append(precheckCall.ID, input...),
+	}, nil)
+	if reason, reverted := clients.RevertReason(err); reverted {
+		return &ValidationError{Code: ReasonPrecheckFailed, Expected: precheckAddr.Hex(), Actual: reason}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to run precheck: %v", err)
+	}
+
+	return nil
+}
+
+func mustPrecheckMethod() abi.Method {
+	outboxAbi, err := bindings.RIP7755OutboxMetaData.GetAbi()
+	if err != nil {
+		panic(err)
+	}
+
+	requestType := outboxAbi.Methods["requestCrossChainCall"].Inputs[0].Type
+	addressType, err := abi.NewType("address", "", nil)
+	if err != nil {
+		panic(err)
+	}
+
+	inputs := abi.Arguments{{Name: "request", Type: requestType}, {Name: "caller", Type: addressType}}
+	return abi.NewMethod("precheckCall", "precheckCall", abi.Function, "view", false, false, inputs, nil)
+}
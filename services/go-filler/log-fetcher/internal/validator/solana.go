@@ -0,0 +1,38 @@
+package validator
+
+import (
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/chains"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/solana"
+)
+
+// Requests bound for Solana are filled by a separate executor through the
+// inbox program in contracts/solana. They are validated like any other
+// request, except that the checks that run EVM calls on the destination are
+// left to the executor, which simulates the fulfill transaction once it has
+// resolved the calls' accounts.
+
+func isSolana(chain *chains.ChainConfig) bool {
+	return chain.ChainKind() == chains.KindSolana
+}
+
+// validateSolanaRequest converts a request bound for Solana into the form the
+// inbox program takes, which has no room for amounts, ids or timestamps that
+// don't fit in a u64.
+func (v *validator) validateSolanaRequest(req *Request) error {
+	r, err := solana.FromLog(req.Log, v.srcChain.ChainId)
+	if err != nil {
+		return &ValidationError{Code: ReasonValueOutOfRange, Actual: err.Error()}
+	}
+	req.Result.Solana = r
+	return nil
+}
+
+// validateSolanaInbox checks that the request is addressed to the inbox
+// program we trust on the destination cluster.
+func validateSolanaInbox(req *Request) error {
+	programId, inbox := req.DstChain.Solana.ProgramId, req.Result.Solana.InboxContract
+	if inbox != programId {
+		return mismatch(ReasonUnknownInbox, programId, inbox)
+	}
+	return nil
+}
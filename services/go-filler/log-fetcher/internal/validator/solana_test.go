@@ -0,0 +1,106 @@
+package validator
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/chains"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/ids"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/profitability"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/provers"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func solanaRequest() *Request {
+	log := *parsedLog
+	log.Request.DestinationChainId = big.NewInt(103)
+	dst := &chains.ChainConfig{
+		Kind:    chains.KindSolana,
+		ChainId: big.NewInt(103),
+		Solana:  &chains.SolanaConfig{ProgramId: ids.Pubkey(ids.AddressToBytes32(log.Request.InboxContract))},
+	}
+
+	return &Request{Log: &log, SrcChain: srcChain, DstChain: dst, Result: &Result{}}
+}
+
+func TestValidateSolanaRequest(t *testing.T) {
+	req := solanaRequest()
+	v := &validator{srcChain: srcChain}
+
+	err := v.validateSolanaRequest(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(103), req.Result.Solana.DestinationChainId)
+	assert.Equal(t, uint64(2000000000000000000), req.Result.Solana.RewardAmount)
+}
+
+func TestValidateSolanaRequest_ValueOutOfRange(t *testing.T) {
+	req := solanaRequest()
+	req.Log.Request.RewardAmount = new(big.Int).Lsh(big.NewInt(1), 64)
+	v := &validator{srcChain: srcChain}
+
+	err := v.validateSolanaRequest(req)
+
+	assert.ErrorIs(t, err, ErrValueOutOfRange)
+	assert.Nil(t, req.Result.Solana)
+}
+
+func TestValidateSolanaInbox(t *testing.T) {
+	req := solanaRequest()
+	v := &validator{srcChain: srcChain}
+	assert.NoError(t, v.validateSolanaRequest(req))
+
+	assert.NoError(t, validateSolanaInbox(req))
+
+	req.DstChain.Solana.ProgramId, _ = ids.ParsePubkey("2nfLnXeeWyAUBsCT8uskj8nvkk46FiwaaVvDx29zQcue")
+	assert.ErrorIs(t, validateSolanaInbox(req), ErrUnknownInbox)
+}
+
+func TestValidateLog_SolanaDestination(t *testing.T) {
+	inbox := parsedLog.Request.InboxContract
+	networks := chains.Networks{
+		"421614": *srcChain,
+		"103": {
+			Kind:    chains.KindSolana,
+			ChainId: big.NewInt(103),
+			Solana:  &chains.SolanaConfig{ProgramId: ids.Pubkey(ids.AddressToBytes32(inbox)), GenesisHash: "EtWTRABZaYq6iMfeYKouRu166VU2xqa1"},
+		},
+	}
+	src := *srcChain
+	src.ShoyuBashi = shoyuBashi
+	src.ProverContracts = map[string]common.Address{string(provers.HashiProver): hashiProver}
+	engineMock := new(EngineMock)
+	engineMock.On("Evaluate", mock.Anything, mock.Anything).Return(&profitability.Estimate{Required: big.NewInt(1)}, nil)
+	validator := newValidator(&src, networks, oracle, engineMock, simulator, nil, nil, dial)
+
+	request := parsedLog.Request
+	request.DestinationChainId = big.NewInt(103)
+	request.ProverContract = hashiProver
+	request.L2Oracle = common.Address{}
+	request.L2OracleStorageKey = [32]byte{}
+	result, err := validator.ValidateLog(decodeLog(t, request))
+
+	assert.NoError(t, err)
+	assert.Equal(t, ids.SolanaChain("EtWTRABZaYq6iMfeYKouRu166VU2xqa1"), result.Destination)
+	assert.Equal(t, uint64(103), result.Solana.DestinationChainId)
+	assert.Equal(t, ids.Pubkey(ids.AddressToBytes32(inbox)), result.Solana.InboxContract)
+}
+
+func TestValidateLog_SolanaDestinationWithoutShoyuBashi(t *testing.T) {
+	networks := chains.Networks{
+		"103": {Kind: chains.KindSolana, ChainId: big.NewInt(103), Solana: &chains.SolanaConfig{ProgramId: ids.Pubkey(ids.AddressToBytes32(parsedLog.Request.InboxContract))}},
+	}
+	src := *srcChain
+	src.ProverContracts = map[string]common.Address{string(provers.HashiProver): hashiProver}
+	validator := newValidator(&src, networks, oracle, new(EngineMock), simulator, nil, nil, dial)
+
+	request := parsedLog.Request
+	request.DestinationChainId = big.NewInt(103)
+	request.ProverContract = hashiProver
+	request.L2Oracle = common.Address{}
+	_, err := validator.ValidateLog(decodeLog(t, request))
+
+	assert.ErrorIs(t, err, ErrMissingShoyuBashi)
+}
@@ -0,0 +1,157 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/base-org/RIP-7755-poc/services/go-filler/bindings"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/chains"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/clients"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// CrossChainCallStatus values, see RIP7755Outbox and RRC7755Outbox.
+const (
+	statusNone uint8 = iota
+	statusRequested
+	statusCanceled
+	statusCompleted
+)
+
+var (
+	getRequestStatus   = mustOutboxMethod("getRequestStatus")
+	getMessageStatus   = newStatusMethod("getMessageStatus")
+	getFulfillmentInfo = mustFulfillmentInfoMethod()
+)
+
+// validateStatus drops requests that were settled since they were emitted:
+// canceled or completed on the source chain, or already fulfilled on an EVM
+// destination. This mostly happens when backfilling or after a restart.
+func (v *validator) validateStatus(ctx context.Context, req *Request) error {
+	status, err := v.outboxStatus(ctx, req.Log.RequestHash)
+	if err != nil {
+		return err
+	}
+
+	switch status {
+	case statusCanceled:
+		return ErrRequestCanceled
+	case statusCompleted:
+		return ErrRequestCompleted
+	}
+
+	// fulfill fails on Solana if the request's fulfillment info account
+	// already exists, so the executor finds out there.
+	if isSolana(req.DstChain) {
+		return nil
+	}
+
+	fulfilledAt, fulfiller, err := v.fulfillmentInfo(ctx, req.DstChain, req.Log.RequestHash)
+	if err != nil {
+		return err
+	}
+	if fulfilledAt.Sign() != 0 {
+		return &ValidationError{Code: ReasonAlreadyFulfilled, Actual: fulfiller.Hex()}
+	}
+
+	return nil
+}
+
+// outboxStatus reads the request's status from the source Outbox. RRC-7755
+// outboxes expose it as getMessageStatus instead of getRequestStatus.
+func (v *validator) outboxStatus(ctx context.Context, requestHash [32]byte) (uint8, error) {
+	client, err := v.dial(v.srcChain)
+	if err != nil {
+		return 0, err
+	}
+
+	var status uint8
+	for _, method := range []abi.Method{getRequestStatus, getMessageStatus} {
+		status, err = callStatus(ctx, client, v.srcChain.Contracts.Outbox, method, requestHash)
+		if _, reverted := clients.RevertReason(err); !reverted {
+			break
+		}
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get request status: %v", err)
+	}
+
+	return status, nil
+}
+
+func callStatus(ctx context.Context, client ChainClient, outbox common.Address, method abi.Method, requestHash [32]byte) (uint8, error) {
+	input, err := method.Inputs.Pack(requestHash)
+	if err != nil {
+		return 0, err
+	}
+
+	out, err := client.CallContract(ctx, ethereum.CallMsg{To: &outbox, Data: append(method.ID, input...)}, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	values, err := method.Outputs.Unpack(out)
+	if err != nil {
+		return 0, err
+	}
+
+	return values[0].(uint8), nil
+}
+
+// fulfillmentInfo reads when and by whom the request was fulfilled on the
+// destination Inbox. The timestamp is zero if it hasn't been.
+func (v *validator) fulfillmentInfo(ctx context.Context, dstChain *chains.ChainConfig, requestHash [32]byte) (*big.Int, common.Address, error) {
+	client, err := v.dial(dstChain)
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+
+	input, err := getFulfillmentInfo.Inputs.Pack(requestHash)
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+
+	inbox := dstChain.Contracts.Inbox
+	out, err := client.CallContract(ctx, ethereum.CallMsg{To: &inbox, Data: append(getFulfillmentInfo.ID, input...)}, nil)
+	if err != nil {
+		return nil, common.Address{}, fmt.Errorf("failed to get fulfillment info: %v", err)
+	}
+
+	values, err := getFulfillmentInfo.Outputs.Unpack(out)
+	if err != nil {
+		return nil, common.Address{}, fmt.Errorf("failed to decode fulfillment info: %v", err)
+	}
+
+	return values[0].(*big.Int), values[1].(common.Address), nil
+}
+
+func mustOutboxMethod(name string) abi.Method {
+	outboxAbi, err := bindings.RIP7755OutboxMetaData.GetAbi()
+	if err != nil {
+		panic(err)
+	}
+
+	return outboxAbi.Methods[name]
+}
+
+func newStatusMethod(name string) abi.Method {
+	bytes32Type, _ := abi.NewType("bytes32", "", nil)
+	uint8Type, _ := abi.NewType("uint8", "", nil)
+
+	return abi.NewMethod(name, name, abi.Function, "view", false, false, abi.Arguments{{Type: bytes32Type}}, abi.Arguments{{Type: uint8Type}})
+}
+
+// mustFulfillmentInfoMethod is Inbox.getFulfillmentInfo(bytes32). It returns a
+// static (uint96, address) tuple, which is encoded the same as two outputs.
+func mustFulfillmentInfoMethod() abi.Method {
+	bytes32Type, _ := abi.NewType("bytes32", "", nil)
+	uint96Type, _ := abi.NewType("uint96", "", nil)
+	addressType, _ := abi.NewType("address", "", nil)
+
+	inputs := abi.Arguments{{Type: bytes32Type}}
+	outputs := abi.Arguments{{Name: "timestamp", Type: uint96Type}, {Name: "fulfiller", Type: addressType}}
+	return abi.NewMethod("getFulfillmentInfo", "getFulfillmentInfo", abi.Function, "view", false, false, inputs, outputs)
+}
@@ -0,0 +1,107 @@
+package validator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/chains"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var (
+	outbox = common.HexToAddress("0x9999999999999999999999999999999999999999")
+	inbox  = common.HexToAddress("0xB482b292878FDe64691d028A2237B34e91c7c7ea")
+)
+
+func calling(to common.Address, selector []byte) interface{} {
+	return mock.MatchedBy(func(msg ethereum.CallMsg) bool {
+		return *msg.To == to && bytes.HasPrefix(msg.Data, selector)
+	})
+}
+
+func packStatus(status uint8) []byte {
+	out, _ := getRequestStatus.Outputs.Pack(status)
+	return out
+}
+
+func packFulfillment(timestamp int64, fulfiller common.Address) []byte {
+	out, _ := getFulfillmentInfo.Outputs.Pack(big.NewInt(timestamp), fulfiller)
+	return out
+}
+
+func newStatusTest(client *ClientMock) (*validator, *Request) {
+	src := &chains.ChainConfig{ChainId: big.NewInt(421614), Contracts: &chains.Contracts{Outbox: outbox}}
+	dst := &chains.ChainConfig{ChainId: big.NewInt(84532), Contracts: &chains.Contracts{Inbox: inbox}}
+	v := &validator{srcChain: src, dial: dialMock(client)}
+
+	return v, &Request{Log: parsedLog, SrcChain: src, DstChain: dst}
+}
+
+func TestValidateStatus_Pending(t *testing.T) {
+	client := new(ClientMock)
+	client.On("CallContract", mock.Anything, calling(outbox, getRequestStatus.ID), mock.Anything).Return(packStatus(statusRequested), nil)
+	client.On("CallContract", mock.Anything, calling(inbox, getFulfillmentInfo.ID), mock.Anything).Return(packFulfillment(0, common.Address{}), nil)
+	v, req := newStatusTest(client)
+
+	assert.NoError(t, v.validateStatus(context.Background(), req))
+}
+
+func TestValidateStatus_Settled(t *testing.T) {
+	testCases := []struct {
+		name     string
+		status   uint8
+		expected error
+	}{
+		{"canceled", statusCanceled, ErrRequestCanceled},
+		{"completed", statusCompleted, ErrRequestCompleted},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := new(ClientMock)
+			client.On("CallContract", mock.Anything, calling(outbox, getRequestStatus.ID), mock.Anything).Return(packStatus(tc.status), nil)
+			v, req := newStatusTest(client)
+
+			assert.ErrorIs(t, v.validateStatus(context.Background(), req), tc.expected)
+			client.AssertNotCalled(t, "CallContract", mock.Anything, calling(inbox, getFulfillmentInfo.ID), mock.Anything)
+		})
+	}
+}
+
+func TestValidateStatus_AlreadyFulfilled(t *testing.T) {
+	client := new(ClientMock)
+	client.On("CallContract", mock.Anything, calling(outbox, getRequestStatus.ID), mock.Anything).Return(packStatus(statusRequested), nil)
+	client.On("CallContract", mock.Anything, calling(inbox, getFulfillmentInfo.ID), mock.Anything).Return(packFulfillment(blockTime, fulfiller), nil)
+	v, req := newStatusTest(client)
+
+	err := v.validateStatus(context.Background(), req)
+
+	assert.ErrorIs(t, err, ErrAlreadyFulfilled)
+	assert.Equal(t, fulfiller.Hex(), err.(*ValidationError).Actual)
+}
+
+func TestValidateStatus_FallsBackToMessageStatus(t *testing.T) {
+	client := new(ClientMock)
+	client.On("CallContract", mock.Anything, calling(outbox, getRequestStatus.ID), mock.Anything).Return([]byte(nil), &revertError{})
+	client.On("CallContract", mock.Anything, calling(outbox, getMessageStatus.ID), mock.Anything).Return(packStatus(statusCompleted), nil)
+	v, req := newStatusTest(client)
+
+	assert.ErrorIs(t, v.validateStatus(context.Background(), req), ErrRequestCompleted)
+}
+
+func TestValidateStatus_RpcError(t *testing.T) {
+	client := new(ClientMock)
+	client.On("CallContract", mock.Anything, calling(outbox, getRequestStatus.ID), mock.Anything).Return([]byte(nil), errors.New("connection refused"))
+	v, req := newStatusTest(client)
+
+	err := v.validateStatus(context.Background(), req)
+
+	assert.Error(t, err)
+	assert.False(t, errors.As(err, new(*ValidationError)))
+}
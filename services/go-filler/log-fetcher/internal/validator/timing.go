@@ -0,0 +1,52 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+)
+
+// validateTiming rejects a request that can't be fulfilled, proven and claimed
+// before it expires. Time is measured from the timestamp of the source block
+// the request was emitted in, so that the check doesn't depend on the local
+// clock or on how far behind the listener is.
+func (v *validator) validateTiming(ctx context.Context, req *Request) error {
+	delay, err := req.Attributes.Delay()
+	if err != nil {
+		return ErrMissingDelay
+	}
+
+	client, err := v.dial(v.srcChain)
+	if err != nil {
+		return err
+	}
+
+	header, err := client.HeaderByNumber(ctx, new(big.Int).SetUint64(req.Log.Raw.BlockNumber))
+	if err != nil {
+		return fmt.Errorf("failed to get source block %d: %v", req.Log.Raw.BlockNumber, err)
+	}
+	now := new(big.Int).SetUint64(header.Time)
+
+	if delay.Expiry.Cmp(now) <= 0 {
+		return &ValidationError{Code: ReasonExpired, Required: now, Offered: delay.Expiry}
+	}
+
+	// The claim needs destination state at least FinalityDelaySeconds newer
+	// than the fulfillment, and that state takes the prover's latency to
+	// become provable on the source chain, unless proofs are mocked.
+	latency := v.srcChain.GetProverLatency(req.Prover)
+	if v.srcChain.MocksProofs(req.DstChain) {
+		latency.ProofSeconds = 0
+	}
+	claimBy := new(big.Int).Set(now)
+	claimBy.Add(claimBy, new(big.Int).SetUint64(latency.FulfillSeconds))
+	claimBy.Add(claimBy, delay.FinalityDelaySeconds)
+	claimBy.Add(claimBy, new(big.Int).SetUint64(latency.ProofSeconds))
+	claimBy.Add(claimBy, new(big.Int).SetUint64(latency.ClaimSeconds))
+
+	if delay.Expiry.Cmp(claimBy) < 0 {
+		return &ValidationError{Code: ReasonInsufficientTime, Required: claimBy, Offered: delay.Expiry}
+	}
+
+	return nil
+}
@@ -1,82 +1,329 @@
 package validator
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"math/big"
 
 	"github.com/base-org/RIP-7755-poc/services/go-filler/bindings"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/attributes"
 	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/chains"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/clients"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/ids"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/policy"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/pricing"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/profitability"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/provers"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/requesters"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/simulation"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/solana"
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
-	logger "github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/core/types"
 )
 
 type Validator interface {
-	ValidateLog(*bindings.RIP7755OutboxCrossChainCallRequested) error
+	ValidateLog(*bindings.RIP7755OutboxCrossChainCallRequested) (*Result, error)
 }
 
+// Result carries what the validator learned about an accepted request.
+// Source, Destination and Requester are the CAIP ids of the route and the
+// requester. NormalizedReward is the reward amount scaled to 18 decimals,
+// RewardValue is the reward converted into the source chain's native asset.
+// CallValue is the native value the calls need on the destination chain,
+// TokenValues the ERC-20 amounts they transfer there, by token. Solana is the request as the inbox program takes it, set for requests bound
+// for Solana.
+type Result struct {
+	Source           ids.ChainID
+	Destination      ids.ChainID
+	Requester        ids.Account
+	RewardAsset      common.Address
+	RewardSymbol     string
+	RewardDecimals   uint8
+	RewardAmount     *big.Int
+	NormalizedReward *big.Int
+	RewardValue      *big.Int
+	CallValue        *big.Int
+	TokenValues      map[common.Address]*big.Int
+	Estimate         *profitability.Estimate
+	Simulation       *simulation.Result
+	Solana           *solana.Request
+}
+
+// validator holds the dependencies of the built-in stages.
 type validator struct {
-	srcChain *chains.ChainConfig
-	networks chains.Networks
+	srcChain      *chains.ChainConfig
+	networks      chains.Networks
+	oracle        pricing.PriceOracle
+	profitability profitability.Engine
+	simulator     simulation.Simulator
+	requesters    *requesters.Guard
+	policy        *policy.Engine
+	dial          func(*chains.ChainConfig) (ChainClient, error)
 }
 
-func NewValidator(srcChain *chains.ChainConfig, networks chains.Networks) Validator {
-	return &validator{srcChain: srcChain, networks: networks}
+// ChainClient is the subset of an Ethereum client needed to check a request
+// against source and destination chain state.
+type ChainClient interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
 }
 
-func (v *validator) ValidateLog(log *bindings.RIP7755OutboxCrossChainCallRequested) error {
-	logger.Info("Validating log")
+// NewValidator returns a chain of the built-in stages followed by any stages
+// added with Register. More can be added with Use. Stages reach chains through
+// pool's clients.
+func NewValidator(srcChain *chains.ChainConfig, networks chains.Networks, oracle pricing.PriceOracle, guard *requesters.Guard, rules *policy.Engine, pool *clients.Pool) *ValidatorChain {
+	v := &validator{srcChain: srcChain, networks: networks, oracle: oracle, profitability: profitability.NewEngine(srcChain, networks, oracle, pool), simulator: simulation.NewSimulator(pool), requesters: guard, policy: rules, dial: func(cfg *chains.ChainConfig) (ChainClient, error) {
+		return pool.Eth(cfg)
+	}}
 
-	// - Confirm valid proverContract address on source chain
-	dstChain, err := v.networks.GetChainConfig(log.Request.DestinationChainId)
+	c := v.chain()
+	for _, s := range registered() {
+		c.Use(s.phase, s.name, s.Stage)
+	}
+	return c
+}
+
+func newValidator(srcChain *chains.ChainConfig, networks chains.Networks, oracle pricing.PriceOracle, engine profitability.Engine, simulator simulation.Simulator, guard *requesters.Guard, rules *policy.Engine, dial func(*chains.ChainConfig) (ChainClient, error)) *ValidatorChain {
+	v := &validator{srcChain: srcChain, networks: networks, oracle: oracle, profitability: engine, simulator: simulator, requesters: guard, policy: rules, dial: dial}
+	return v.chain()
+}
+
+// chain returns the built-in stages. The status check reads the source and
+// destination chains, so it runs once the route checks have passed.
+func (v *validator) chain() *ValidatorChain {
+	return NewValidatorChain(v.srcChain).
+		Use(Structural, "requester", StageFunc(v.validateRequester)).
+		Use(Structural, "request", StageFunc(v.validateRequest)).
+		Use(Route, "contracts", StageFunc(v.validateContracts)).
+		Use(Route, "calls", StageFunc(v.validateCalls)).
+		Use(Route, "timing", StageFunc(v.validateTiming)).
+		Use(Route, "status", StageFunc(v.validateStatus)).
+		Use(Economic, "profitability", StageFunc(v.validateProfitability)).
+		Use(Economic, "policy", StageFunc(v.validatePolicy)).
+		Use(Simulation, "precheck", StageFunc(v.validatePrecheck)).
+		Use(Simulation, "fulfill", StageFunc(v.validateFulfill))
+}
+
+// validateRequester checks that the requester isn't blocked or flooding us.
+// It runs first since everything after costs RPC calls.
+func (v *validator) validateRequester(ctx context.Context, req *Request) error {
+	switch v.requesters.Check(req.Log.Request.Requester, req.Log.RequestHash) {
+	case requesters.Blocked:
+		return &ValidationError{Code: ReasonRequesterBlocked, Actual: req.Log.Request.Requester.Hex()}
+	case requesters.Throttled:
+		return &ValidationError{Code: ReasonRateLimited, Actual: req.Log.Request.Requester.Hex()}
+	}
+	return nil
+}
+
+// validateRequest resolves the destination chain, attributes and total call
+// value, and checks that the reward covers the call value.
+func (v *validator) validateRequest(ctx context.Context, req *Request) error {
+	dstChain, err := v.networks.GetChainConfig(req.Log.Request.DestinationChainId)
 	if err != nil {
-		return err
+		return &ValidationError{Code: ReasonUnknownDestinationChain, Actual: req.Log.Request.DestinationChainId.String()}
 	}
+	req.DstChain = dstChain
+	req.Prover = v.srcChain.SelectProver(dstChain)
+	req.Result.Source, req.Result.Destination = v.srcChain.ID(), dstChain.ID()
+	req.Result.Requester = v.srcChain.Account(req.Log.Request.Requester)
 
-	proverName := string(dstChain.TargetProver)
+	if isSolana(dstChain) {
+		if err := v.validateSolanaRequest(req); err != nil {
+			return err
+		}
+	}
+
+	// - Add up total value needed
+	req.CallValue = big.NewInt(0)
+	for _, call := range req.Log.Request.Calls {
+		req.CallValue.Add(req.CallValue, call.Value)
+	}
+	req.Result.CallValue = req.CallValue
+	if !isSolana(dstChain) {
+		req.TokenValues = tokenValues(dstChain, req.Log.Request.Calls)
+		req.Result.TokenValues = req.TokenValues
+	}
+
+	// - rewardAsset + rewardAmount should make sense given requested calls
+	req.Attributes = attributes.FromRequest(&req.Log.Request)
+	return v.validateReward(req)
+}
+
+// validateContracts checks the prover, inbox and l2 oracle against the ones we
+// trust for the route.
+func (v *validator) validateContracts(ctx context.Context, req *Request) error {
+	request, dstChain := &req.Log.Request, req.DstChain
+
+	// - Confirm valid proverContract address on source chain
+	proverName := string(req.Prover)
 	if proverName == "" {
-		return errors.New("destination chain missing Prover name")
+		return ErrMissingProverName
 	}
 
 	expectedProverAddr := v.srcChain.ProverContracts[proverName]
 	if expectedProverAddr == common.HexToAddress("") {
-		return errors.New("expected prover address not found for source chain")
+		return &ValidationError{Code: ReasonProverNotConfigured, Expected: proverName}
 	}
 
-	if log.Request.ProverContract != expectedProverAddr {
-		return errors.New("unknown Prover contract")
+	if request.ProverContract != expectedProverAddr {
+		return mismatch(ReasonUnknownProver, expectedProverAddr, request.ProverContract)
 	}
 
 	// - Make sure inboxContract matches the trusted inbox for dst chain Id
-	if log.Request.InboxContract != dstChain.Contracts.Inbox {
-		return errors.New("unknown Inbox contract on destination chain")
+	if isSolana(dstChain) {
+		if err := validateSolanaInbox(req); err != nil {
+			return err
+		}
+	} else if request.InboxContract != dstChain.Contracts.Inbox {
+		return mismatch(ReasonUnknownInbox, dstChain.Contracts.Inbox, request.InboxContract)
+	}
+
+	if req.Prover == provers.HashiProver {
+		return v.validateShoyuBashi(req)
 	}
 
 	// - Confirm l2Oracle and l2OracleStorageKey are valid for dst chain
-	if log.Request.L2Oracle != dstChain.L2Oracle {
-		return errors.New("unknown Oracle contract for destination chain")
+	if request.L2Oracle != dstChain.L2Oracle {
+		return mismatch(ReasonUnknownL2Oracle, dstChain.L2Oracle, request.L2Oracle)
 	}
-	if log.Request.L2OracleStorageKey != common.HexToHash(dstChain.L2OracleStorageKey) {
-		return errors.New("unknown storage key for dst L2Oracle")
+	expectedStorageKey := common.HexToHash(dstChain.L2OracleStorageKey)
+	if request.L2OracleStorageKey != expectedStorageKey {
+		return mismatch(ReasonUnknownL2OracleKey, expectedStorageKey, common.Hash(request.L2OracleStorageKey))
 	}
 
-	// - Add up total value needed
-	valueNeeded := big.NewInt(0)
+	return nil
+}
 
-	for i := 0; i < len(log.Request.Calls); i++ {
-		valueNeeded.Add(valueNeeded, log.Request.Calls[i].Value)
+// validateShoyuBashi checks the oracle of a Hashi route. Hashi proofs are read
+// from the ShoyuBashi we trust on the source chain and don't use an L2 oracle.
+// RIP-7755 requests have no attributes to name a ShoyuBashi in, so they are
+// proven against the configured one. A request that names one must name it.
+func (v *validator) validateShoyuBashi(req *Request) error {
+	if req.Log.Request.L2Oracle != (common.Address{}) {
+		return mismatch(ReasonUnknownL2Oracle, common.Address{}, req.Log.Request.L2Oracle)
 	}
 
-	// - rewardAsset + rewardAmount should make sense given requested calls
-	if !isValidReward(&log.Request, valueNeeded) {
-		return errors.New("undesirable reward")
+	if v.srcChain.ShoyuBashi == (common.Address{}) {
+		return &ValidationError{Code: ReasonMissingShoyuBashi, Expected: "shoyu-bashi"}
+	}
+
+	shoyuBashi, err := req.Attributes.ShoyuBashi()
+	if errors.Is(err, attributes.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return &ValidationError{Code: ReasonUnknownShoyuBashi, Expected: v.srcChain.ShoyuBashi.Hex(), Actual: err.Error()}
+	}
+
+	addr, ok := ids.Bytes32ToAddress(shoyuBashi)
+	if !ok {
+		return &ValidationError{Code: ReasonUnknownShoyuBashi, Expected: v.srcChain.ShoyuBashi.Hex(), Actual: common.Hash(shoyuBashi).Hex()}
+	}
+	if addr != v.srcChain.ShoyuBashi {
+		return mismatch(ReasonUnknownShoyuBashi, v.srcChain.ShoyuBashi, addr)
 	}
 
 	return nil
 }
 
-func isValidReward(request *bindings.CrossChainRequest, valueNeeded *big.Int) bool {
-	nativeAssetAddr := common.HexToAddress("0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE")
-	isGreaterThan := request.RewardAmount.Cmp(valueNeeded) == 1
+// validateProfitability checks that the reward covers gas on both chains plus
+// the route's minimum margin.
+func (v *validator) validateProfitability(ctx context.Context, req *Request) error {
+	result := req.Result
 
-	return request.RewardAsset == nativeAssetAddr && isGreaterThan
+	var err error
+	result.Estimate, err = v.profitability.Evaluate(ctx, &req.Log.Request)
+	if err != nil {
+		return fmt.Errorf("failed to estimate request cost: %v", err)
+	}
+
+	rewardAsset := pricing.Asset{Symbol: result.RewardSymbol, Decimals: result.RewardDecimals}
+	srcNative := pricing.Asset{Symbol: v.srcChain.NativeAssetSymbol(), Decimals: v.srcChain.NativeAssetDecimals()}
+	result.RewardValue, err = pricing.Convert(ctx, v.oracle, result.RewardAmount, rewardAsset, srcNative)
+	if err != nil {
+		return fmt.Errorf("failed to price reward: %v", err)
+	}
+
+	if result.RewardValue.Cmp(result.Estimate.Required) < 0 {
+		return &ValidationError{Code: ReasonUnprofitable, Required: result.Estimate.Required, Offered: result.RewardValue}
+	}
+
+	return nil
+}
+
+// validatePolicy holds the request to the policy for its route.
+func (v *validator) validatePolicy(ctx context.Context, req *Request) error {
+	violation := v.policy.Evaluate(policy.Input{
+		SourceChainId:      v.srcChain.ChainId.String(),
+		DestinationChainId: req.Log.Request.DestinationChainId.String(),
+		Prover:             req.Prover,
+		CallValue:          req.CallValue,
+		RewardSymbol:       req.Result.RewardSymbol,
+		Attributes:         req.Attributes,
+		Cost:               req.Result.Estimate.Cost,
+		RewardValue:        req.Result.RewardValue,
+	})
+	if violation != nil {
+		return policyViolation(violation)
+	}
+	return nil
+}
+
+// validateFulfill checks that the whole fulfillment goes through for our
+// fulfiller.
+func (v *validator) validateFulfill(ctx context.Context, req *Request) error {
+	if isSolana(req.DstChain) {
+		return nil
+	}
+
+	sim, err := v.simulator.SimulateFulfill(ctx, &req.Log.Request, req.DstChain)
+	if err != nil {
+		return err
+	}
+	req.Result.Simulation = sim
+
+	if !sim.Success {
+		return &ValidationError{Code: ReasonSimulationReverted, Actual: sim.RevertReason}
+	}
+	return nil
+}
+
+func (v *validator) validateReward(req *Request) error {
+	result := req.Result
+
+	reward, err := req.Attributes.Reward()
+	if err != nil {
+		return &ValidationError{Code: ReasonUnsupportedRewardAsset}
+	}
+
+	rewardAsset, ok := reward.AssetAddress()
+	if !ok {
+		return &ValidationError{Code: ReasonUnsupportedRewardAsset, Actual: common.Hash(reward.Asset).Hex()}
+	}
+
+	if rewardAsset == attributes.NativeAsset {
+		if reward.Amount.Cmp(req.CallValue) != 1 {
+			return &ValidationError{Code: ReasonInsufficientReward, Required: req.CallValue, Offered: reward.Amount}
+		}
+
+		result.RewardAsset, result.RewardSymbol, result.RewardDecimals = rewardAsset, v.srcChain.NativeAssetSymbol(), 18
+		result.RewardAmount, result.NormalizedReward = reward.Amount, reward.Amount
+		return nil
+	}
+
+	token, ok := v.srcChain.GetRewardToken(rewardAsset)
+	if !ok {
+		return &ValidationError{Code: ReasonUnsupportedRewardAsset, Actual: rewardAsset.Hex()}
+	}
+
+	if token.MinAmount != nil && reward.Amount.Cmp(token.MinAmount) < 0 {
+		return &ValidationError{Code: ReasonInsufficientReward, Required: token.MinAmount, Offered: reward.Amount}
+	}
+
+	result.RewardAsset, result.RewardSymbol, result.RewardDecimals = rewardAsset, token.Symbol, token.Decimals
+	result.RewardAmount, result.NormalizedReward = reward.Amount, token.Normalize(reward.Amount)
+	return nil
 }
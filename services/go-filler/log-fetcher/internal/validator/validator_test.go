@@ -1,24 +1,40 @@
 package validator
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"math/big"
 	"testing"
+	"time"
 
 	"github.com/base-org/RIP-7755-poc/services/go-filler/bindings"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/attributes"
 	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/chains"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/ids"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/policy"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/pricing"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/profitability"
 	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/provers"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/requesters"
+	"github.com/base-org/RIP-7755-poc/services/go-filler/log-fetcher/internal/simulation"
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 var networksCfg chains.NetworksConfig = chains.NetworksConfig{
 	Networks: chains.Networks{
 		"421614": chains.ChainConfig{
+			ChainId: big.NewInt(421614),
 			ProverContracts: map[string]common.Address{
 				"OPStack": common.HexToAddress("0x1234567890123456789012345678901234567890"),
 			},
 		},
 		"84532": chains.ChainConfig{
+			ChainId: big.NewInt(84532),
 			Contracts: &chains.Contracts{
 				Inbox: common.HexToAddress("0xB482b292878FDe64691d028A2237B34e91c7c7ea"),
 			},
@@ -29,11 +45,17 @@ var networksCfg chains.NetworksConfig = chains.NetworksConfig{
 	},
 }
 
+var usdc = common.HexToAddress("0x75faf114eafb1BDbe2F0316DF893fd58CE46AA4d")
+
 var srcChain = &chains.ChainConfig{
-	ChainId: big.NewInt(421614),
+	ChainId:   big.NewInt(421614),
+	Contracts: &chains.Contracts{Outbox: outbox},
 	ProverContracts: map[string]common.Address{
 		"OPStack": common.HexToAddress("0x1234567890123456789012345678901234567890"),
 	},
+	RewardTokens: []chains.TokenConfig{
+		{Symbol: "USDC", Address: usdc, Decimals: 6, MinAmount: big.NewInt(1_000_000)},
+	},
 }
 
 var parsedLog = &bindings.RIP7755OutboxCrossChainCallRequested{
@@ -44,116 +66,708 @@ var parsedLog = &bindings.RIP7755OutboxCrossChainCallRequested{
 				Value: big.NewInt(1000000000000000000),
 			},
 		},
-		DestinationChainId: big.NewInt(84532),
-		InboxContract:      common.HexToAddress("0xB482b292878FDe64691d028A2237B34e91c7c7ea"),
-		L2Oracle:           common.HexToAddress("0x4C8BA32A5DAC2A720bb35CeDB51D6B067D104205"),
-		L2OracleStorageKey: common.HexToHash("0xa6eef7e35abe7026729641147f7915573c7e97b47efa546f5f6e3230263bcb49"),
-		ProverContract:     common.HexToAddress("0x1234567890123456789012345678901234567890"),
-		RewardAsset:        common.HexToAddress("0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE"),
-		RewardAmount:       big.NewInt(2000000000000000000),
+		DestinationChainId:   big.NewInt(84532),
+		InboxContract:        common.HexToAddress("0xB482b292878FDe64691d028A2237B34e91c7c7ea"),
+		L2Oracle:             common.HexToAddress("0x4C8BA32A5DAC2A720bb35CeDB51D6B067D104205"),
+		L2OracleStorageKey:   common.HexToHash("0xa6eef7e35abe7026729641147f7915573c7e97b47efa546f5f6e3230263bcb49"),
+		ProverContract:       common.HexToAddress("0x1234567890123456789012345678901234567890"),
+		RewardAsset:          common.HexToAddress("0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE"),
+		RewardAmount:         big.NewInt(2000000000000000000),
+		FinalityDelaySeconds: big.NewInt(0),
+		Nonce:                big.NewInt(1),
+		Expiry:               big.NewInt(blockTime + 30*24*60*60),
 	},
+	Raw: types.Log{BlockNumber: 100},
+}
+
+const blockTime = 1_700_000_000
+
+// decodeLog returns request as the listener gets it, packed into a
+// CrossChainCallRequested event and decoded by the outbox bindings.
+func decodeLog(t *testing.T, request bindings.CrossChainRequest) *bindings.RIP7755OutboxCrossChainCallRequested {
+	outboxABI, err := bindings.RIP7755OutboxMetaData.GetAbi()
+	assert.NoError(t, err)
+	event := outboxABI.Events["CrossChainCallRequested"]
+
+	data, err := event.Inputs.NonIndexed().Pack(request)
+	assert.NoError(t, err)
+
+	filterer, err := bindings.NewRIP7755OutboxFilterer(common.Address{}, nil)
+	assert.NoError(t, err)
+	log, err := filterer.ParseCrossChainCallRequested(types.Log{Topics: []common.Hash{event.ID, common.HexToHash("0x01")}, Data: data, BlockNumber: 100})
+	assert.NoError(t, err)
+
+	return log
+}
+
+type ClientMock struct {
+	mock.Mock
+}
+
+func (c *ClientMock) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return &types.Header{Number: number, Time: blockTime}, nil
+}
+
+func (c *ClientMock) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	args := c.Called(ctx, msg, blockNumber)
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func dialMock(client *ClientMock) func(*chains.ChainConfig) (ChainClient, error) {
+	return func(*chains.ChainConfig) (ChainClient, error) {
+		return client, nil
+	}
+}
+
+// pending makes client report requests as pending on the source chain and not
+// yet fulfilled on the destination.
+func pending(client *ClientMock) *ClientMock {
+	client.On("CallContract", mock.Anything, calling(outbox, getRequestStatus.ID), mock.Anything).Return(packStatus(statusRequested), nil)
+	client.On("CallContract", mock.Anything, calling(inbox, getFulfillmentInfo.ID), mock.Anything).Return(packFulfillment(0, common.Address{}), nil)
+	return client
+}
+
+var dial = dialMock(pending(new(ClientMock)))
+
+type SimulatorMock struct {
+	mock.Mock
+}
+
+func (s *SimulatorMock) SimulateFulfill(ctx context.Context, request *bindings.CrossChainRequest, dstChain *chains.ChainConfig) (*simulation.Result, error) {
+	args := s.Called(ctx, request, dstChain)
+	return args.Get(0).(*simulation.Result), args.Error(1)
+}
+
+var simulated = &simulation.Result{Success: true, GasUsed: 210000}
+
+var simulator = newSimulatorMock(simulated, nil)
+
+func newSimulatorMock(result *simulation.Result, err error) *SimulatorMock {
+	simulatorMock := new(SimulatorMock)
+	simulatorMock.On("SimulateFulfill", mock.Anything, mock.Anything, mock.Anything).Return(result, err)
+	return simulatorMock
+}
+
+type EngineMock struct {
+	mock.Mock
+}
+
+func (e *EngineMock) Evaluate(ctx context.Context, request *bindings.CrossChainRequest) (*profitability.Estimate, error) {
+	args := e.Called(ctx, request)
+	return args.Get(0).(*profitability.Estimate), args.Error(1)
+}
+
+var oracle, _ = pricing.NewStaticOracle(pricing.PricesConfig{Prices: map[string]string{"ETH": "2000", "USDC": "1"}})
+
+func newTestValidator() *ValidatorChain {
+	engineMock := new(EngineMock)
+	engineMock.On("Evaluate", mock.Anything, mock.Anything).Return(&profitability.Estimate{Required: big.NewInt(1500000000000000000)}, nil)
+
+	return newValidator(srcChain, networksCfg.Networks, oracle, engineMock, simulator, nil, nil, dial)
 }
 
 func TestValidateLog(t *testing.T) {
-	validator := NewValidator(srcChain, networksCfg.Networks)
+	validator := newTestValidator()
 
-	err := validator.ValidateLog(parsedLog)
+	result, err := validator.ValidateLog(parsedLog)
 
 	assert.NoError(t, err)
+	assert.Equal(t, parsedLog.Request.RewardAsset, result.RewardAsset)
+	assert.Equal(t, "ETH", result.RewardSymbol)
+	assert.Equal(t, parsedLog.Request.RewardAmount, result.NormalizedReward)
+	assert.Equal(t, srcChain.ID(), result.Source)
+	assert.Equal(t, ids.EVMChain(parsedLog.Request.DestinationChainId), result.Destination)
+	assert.Equal(t, ids.EVMAccount(srcChain.ChainId, parsedLog.Request.Requester), result.Requester)
 }
 
 func TestValidateLog_UnknownDestinationChain(t *testing.T) {
-	validator := NewValidator(srcChain, networksCfg.Networks)
+	validator := newTestValidator()
 
 	prevDstChainId := parsedLog.Request.DestinationChainId
 	parsedLog.Request.DestinationChainId = big.NewInt(11155112)
 	defer func() { parsedLog.Request.DestinationChainId = prevDstChainId }()
 
-	err := validator.ValidateLog(parsedLog)
+	_, err := validator.ValidateLog(parsedLog)
 
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, ErrUnknownDestinationChain)
 }
 
 func TestValidateLog_UnknownProverName(t *testing.T) {
-	validator := NewValidator(srcChain, networksCfg.Networks)
+	validator := newTestValidator()
 
 	prevDstChainId := parsedLog.Request.DestinationChainId
 	parsedLog.Request.DestinationChainId = big.NewInt(11155111)
 	defer func() { parsedLog.Request.DestinationChainId = prevDstChainId }()
 
-	err := validator.ValidateLog(parsedLog)
+	_, err := validator.ValidateLog(parsedLog)
 
 	assert.Error(t, err)
 }
 
 func TestValidateLog_UnknownProverContract(t *testing.T) {
-	validator := NewValidator(srcChain, networksCfg.Networks)
+	validator := newTestValidator()
 
 	prevProverContract := parsedLog.Request.ProverContract
 	parsedLog.Request.ProverContract = common.HexToAddress("0x1234567890123456789012345678901234567891")
 	defer func() { parsedLog.Request.ProverContract = prevProverContract }()
 
-	err := validator.ValidateLog(parsedLog)
+	_, err := validator.ValidateLog(parsedLog)
 
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, ErrUnknownProver)
 }
 
 func TestValidateLog_UnknownInboxContract(t *testing.T) {
-	validator := NewValidator(srcChain, networksCfg.Networks)
+	validator := newTestValidator()
 
 	prevInboxContract := parsedLog.Request.InboxContract
 	parsedLog.Request.InboxContract = common.HexToAddress("0x1234567890123456789012345678901234567891")
 	defer func() { parsedLog.Request.InboxContract = prevInboxContract }()
 
-	err := validator.ValidateLog(parsedLog)
+	_, err := validator.ValidateLog(parsedLog)
 
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, ErrUnknownInbox)
 }
 
 func TestValidateLog_UnknownL2Oracle(t *testing.T) {
-	validator := NewValidator(srcChain, networksCfg.Networks)
+	validator := newTestValidator()
 
 	prevL2Oracle := parsedLog.Request.L2Oracle
 	parsedLog.Request.L2Oracle = common.HexToAddress("0x1234567890123456789012345678901234567891")
 	defer func() { parsedLog.Request.L2Oracle = prevL2Oracle }()
 
-	err := validator.ValidateLog(parsedLog)
+	_, err := validator.ValidateLog(parsedLog)
 
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, ErrUnknownL2Oracle)
 }
 
 func TestValidateLog_UnknownL2OracleStorageKey(t *testing.T) {
-	validator := NewValidator(srcChain, networksCfg.Networks)
+	validator := newTestValidator()
 
 	prevL2OracleStorageKey := parsedLog.Request.L2OracleStorageKey
 	parsedLog.Request.L2OracleStorageKey = common.HexToHash("0x1234567890123456789012345678901234567891")
 	defer func() { parsedLog.Request.L2OracleStorageKey = prevL2OracleStorageKey }()
 
-	err := validator.ValidateLog(parsedLog)
+	_, err := validator.ValidateLog(parsedLog)
 
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, ErrUnknownL2OracleKey)
 }
 
 func TestValidateLog_InvalidReward_NotNativeAsset(t *testing.T) {
-	validator := NewValidator(srcChain, networksCfg.Networks)
+	validator := newTestValidator()
 
 	prevRewardAsset := parsedLog.Request.RewardAsset
 	parsedLog.Request.RewardAsset = common.HexToAddress("0x1234567890123456789012345678901234567891")
 	defer func() { parsedLog.Request.RewardAsset = prevRewardAsset }()
 
-	err := validator.ValidateLog(parsedLog)
+	_, err := validator.ValidateLog(parsedLog)
 
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, ErrUnsupportedRewardAsset)
 }
 
 func TestValidateLog_InvalidReward_NotGreaterThanValueNeeded(t *testing.T) {
-	validator := NewValidator(srcChain, networksCfg.Networks)
+	validator := newTestValidator()
 
 	prevRewardAmount := parsedLog.Request.RewardAmount
 	parsedLog.Request.RewardAmount = big.NewInt(1000000000000000000)
 	defer func() { parsedLog.Request.RewardAmount = prevRewardAmount }()
 
-	err := validator.ValidateLog(parsedLog)
+	_, err := validator.ValidateLog(parsedLog)
+
+	assert.ErrorIs(t, err, ErrInsufficientReward)
+}
+
+func TestValidateLog_Unprofitable(t *testing.T) {
+	engineMock := new(EngineMock)
+	engineMock.On("Evaluate", mock.Anything, &parsedLog.Request).Return(&profitability.Estimate{Required: big.NewInt(2500000000000000000)}, nil)
+	validator := newValidator(srcChain, networksCfg.Networks, oracle, engineMock, simulator, nil, nil, dial)
+
+	_, err := validator.ValidateLog(parsedLog)
 
+	assert.ErrorIs(t, err, ErrUnprofitable)
+	engineMock.AssertExpectations(t)
+}
+
+func TestValidateLog_EstimateError(t *testing.T) {
+	engineMock := new(EngineMock)
+	engineMock.On("Evaluate", mock.Anything, mock.Anything).Return((*profitability.Estimate)(nil), errors.New("rpc error"))
+	validator := newValidator(srcChain, networksCfg.Networks, oracle, engineMock, simulator, nil, nil, dial)
+
+	_, err := validator.ValidateLog(parsedLog)
+
+	var vErr *ValidationError
 	assert.Error(t, err)
+	assert.False(t, errors.As(err, &vErr))
+}
+
+func TestValidateLog_TokenReward(t *testing.T) {
+	validator := newTestValidator()
+
+	prevRewardAsset, prevRewardAmount := parsedLog.Request.RewardAsset, parsedLog.Request.RewardAmount
+	parsedLog.Request.RewardAsset = usdc
+	parsedLog.Request.RewardAmount = big.NewInt(5_000_000_000)
+	defer func() {
+		parsedLog.Request.RewardAsset, parsedLog.Request.RewardAmount = prevRewardAsset, prevRewardAmount
+	}()
+
+	result, err := validator.ValidateLog(parsedLog)
+
+	assert.NoError(t, err)
+	assert.Equal(t, usdc, result.RewardAsset)
+	assert.Equal(t, "USDC", result.RewardSymbol)
+	assert.Equal(t, big.NewInt(5_000_000_000), result.RewardAmount)
+	assert.Equal(t, new(big.Int).Mul(big.NewInt(5_000), big.NewInt(1e18)), result.NormalizedReward)
+	assert.Equal(t, new(big.Int).Mul(big.NewInt(25), big.NewInt(1e17)), result.RewardValue)
+}
+
+func TestValidateLog_TokenRewardBelowMinimum(t *testing.T) {
+	validator := newTestValidator()
+
+	prevRewardAsset, prevRewardAmount := parsedLog.Request.RewardAsset, parsedLog.Request.RewardAmount
+	parsedLog.Request.RewardAsset = usdc
+	parsedLog.Request.RewardAmount = big.NewInt(999_999)
+	defer func() {
+		parsedLog.Request.RewardAsset, parsedLog.Request.RewardAmount = prevRewardAsset, prevRewardAmount
+	}()
+
+	_, err := validator.ValidateLog(parsedLog)
+
+	assert.ErrorIs(t, err, ErrInsufficientReward)
+}
+
+func TestValidateLog_TokenRewardUnprofitable(t *testing.T) {
+	validator := newTestValidator()
+
+	// 2000 USDC is worth 1 ETH, below the 1.5 ETH required
+	prevRewardAsset, prevRewardAmount := parsedLog.Request.RewardAsset, parsedLog.Request.RewardAmount
+	parsedLog.Request.RewardAsset = usdc
+	parsedLog.Request.RewardAmount = big.NewInt(2_000_000_000)
+	defer func() {
+		parsedLog.Request.RewardAsset, parsedLog.Request.RewardAmount = prevRewardAsset, prevRewardAmount
+	}()
+
+	_, err := validator.ValidateLog(parsedLog)
+
+	assert.ErrorIs(t, err, ErrUnprofitable)
+}
+
+func TestValidateLog_TokenRewardUnpriced(t *testing.T) {
+	engineMock := new(EngineMock)
+	engineMock.On("Evaluate", mock.Anything, mock.Anything).Return(&profitability.Estimate{Required: big.NewInt(1)}, nil)
+	emptyOracle, _ := pricing.NewStaticOracle(pricing.PricesConfig{})
+	validator := newValidator(srcChain, networksCfg.Networks, emptyOracle, engineMock, simulator, nil, nil, dial)
+
+	prevRewardAsset, prevRewardAmount := parsedLog.Request.RewardAsset, parsedLog.Request.RewardAmount
+	parsedLog.Request.RewardAsset = usdc
+	parsedLog.Request.RewardAmount = big.NewInt(5_000_000_000)
+	defer func() {
+		parsedLog.Request.RewardAsset, parsedLog.Request.RewardAmount = prevRewardAsset, prevRewardAmount
+	}()
+
+	_, err := validator.ValidateLog(parsedLog)
+
+	assert.Error(t, err)
+}
+
+func TestValidateLog_Expired(t *testing.T) {
+	validator := newTestValidator()
+
+	prevExpiry := parsedLog.Request.Expiry
+	parsedLog.Request.Expiry = big.NewInt(blockTime)
+	defer func() { parsedLog.Request.Expiry = prevExpiry }()
+
+	_, err := validator.ValidateLog(parsedLog)
+
+	assert.ErrorIs(t, err, ErrExpired)
+}
+
+func TestValidateLog_InsufficientTimeForProof(t *testing.T) {
+	validator := newTestValidator()
+
+	// OPStack proofs take 4 days by default
+	prevExpiry := parsedLog.Request.Expiry
+	parsedLog.Request.Expiry = big.NewInt(blockTime + 3*24*60*60)
+	defer func() { parsedLog.Request.Expiry = prevExpiry }()
+
+	_, err := validator.ValidateLog(parsedLog)
+
+	assert.ErrorIs(t, err, ErrInsufficientTime)
+}
+
+func TestValidateLog_InsufficientTimeForFinalityDelay(t *testing.T) {
+	validator := newTestValidator()
+
+	prevDelay := parsedLog.Request.FinalityDelaySeconds
+	parsedLog.Request.FinalityDelaySeconds = big.NewInt(27 * 24 * 60 * 60)
+	defer func() { parsedLog.Request.FinalityDelaySeconds = prevDelay }()
+
+	_, err := validator.ValidateLog(parsedLog)
+
+	assert.ErrorIs(t, err, ErrInsufficientTime)
+}
+
+func TestValidateLog_ConfiguredProverLatency(t *testing.T) {
+	engineMock := new(EngineMock)
+	engineMock.On("Evaluate", mock.Anything, mock.Anything).Return(&profitability.Estimate{Required: big.NewInt(1)}, nil)
+	fastSrc := *srcChain
+	fastSrc.ProverLatency = map[string]provers.Latency{
+		string(provers.OPStackProver): {FulfillSeconds: 60, ProofSeconds: 3600, ClaimSeconds: 60},
+	}
+	validator := newValidator(&fastSrc, networksCfg.Networks, oracle, engineMock, simulator, nil, nil, dial)
+
+	prevExpiry := parsedLog.Request.Expiry
+	parsedLog.Request.Expiry = big.NewInt(blockTime + 2*60*60)
+	defer func() { parsedLog.Request.Expiry = prevExpiry }()
+
+	_, err := validator.ValidateLog(parsedLog)
+
+	assert.NoError(t, err)
+}
+
+func TestValidateLog_MockedProofs(t *testing.T) {
+	engineMock := new(EngineMock)
+	engineMock.On("Evaluate", mock.Anything, mock.Anything).Return(&profitability.Estimate{Required: big.NewInt(1)}, nil)
+	devnetSrc := *srcChain
+	devnetSrc.MockProofs = true
+	validator := newValidator(&devnetSrc, networksCfg.Networks, oracle, engineMock, simulator, nil, nil, dial)
+
+	// only the default fulfill and claim latency of 5 minutes each is left
+	prevExpiry := parsedLog.Request.Expiry
+	parsedLog.Request.Expiry = big.NewInt(blockTime + 10*60)
+	defer func() { parsedLog.Request.Expiry = prevExpiry }()
+
+	_, err := validator.ValidateLog(parsedLog)
+	assert.NoError(t, err)
+
+	parsedLog.Request.Expiry = big.NewInt(blockTime + 10*60 - 1)
+	_, err = validator.ValidateLog(parsedLog)
+	assert.ErrorIs(t, err, ErrInsufficientTime)
+}
+
+type revertError struct{}
+
+func (e *revertError) Error() string  { return "execution reverted" }
+func (e *revertError) ErrorCode() int { return 3 }
+
+var precheck = common.HexToAddress("0x3333333333333333333333333333333333333333")
+
+func newPrecheckValidator(client *ClientMock) *ValidatorChain {
+	engineMock := new(EngineMock)
+	engineMock.On("Evaluate", mock.Anything, mock.Anything).Return(&profitability.Estimate{Required: big.NewInt(1)}, nil)
+
+	networks := chains.Networks{}
+	for id, cfg := range networksCfg.Networks {
+		cfg.ChainId, _ = new(big.Int).SetString(id, 10)
+		cfg.Fulfiller = common.HexToAddress("0x4444444444444444444444444444444444444444")
+		networks[id] = cfg
+	}
+
+	return newValidator(srcChain, networks, oracle, engineMock, simulator, nil, nil, dialMock(pending(client)))
+}
+
+func TestValidateLog_PrecheckPasses(t *testing.T) {
+	client := new(ClientMock)
+	client.On("CallContract", mock.Anything, mock.MatchedBy(func(msg ethereum.CallMsg) bool {
+		return *msg.To == precheck &&
+			msg.From == common.HexToAddress("0xB482b292878FDe64691d028A2237B34e91c7c7ea") &&
+			bytes.Equal(msg.Data[:4], precheckCall.ID)
+	}), mock.Anything).Return([]byte{}, nil)
+	validator := newPrecheckValidator(client)
+
+	parsedLog.Request.PrecheckContract = precheck
+	defer func() { parsedLog.Request.PrecheckContract = common.Address{} }()
+
+	_, err := validator.ValidateLog(parsedLog)
+
+	assert.NoError(t, err)
+	client.AssertExpectations(t)
+}
+
+func TestValidateLog_PrecheckReverts(t *testing.T) {
+	client := new(ClientMock)
+	client.On("CallContract", mock.Anything, calling(precheck, nil), mock.Anything).Return([]byte(nil), &revertError{})
+	validator := newPrecheckValidator(client)
+
+	parsedLog.Request.PrecheckContract = precheck
+	defer func() { parsedLog.Request.PrecheckContract = common.Address{} }()
+
+	_, err := validator.ValidateLog(parsedLog)
+
+	assert.ErrorIs(t, err, ErrPrecheckFailed)
+}
+
+func TestValidateLog_PrecheckRpcError(t *testing.T) {
+	client := new(ClientMock)
+	client.On("CallContract", mock.Anything, calling(precheck, nil), mock.Anything).Return([]byte(nil), errors.New("connection refused"))
+	validator := newPrecheckValidator(client)
+
+	parsedLog.Request.PrecheckContract = precheck
+	defer func() { parsedLog.Request.PrecheckContract = common.Address{} }()
+
+	_, err := validator.ValidateLog(parsedLog)
+
+	var vErr *ValidationError
+	assert.Error(t, err)
+	assert.False(t, errors.As(err, &vErr))
+}
+
+func TestValidateLog_PrecheckWithoutFulfiller(t *testing.T) {
+	validator := newTestValidator()
+
+	parsedLog.Request.PrecheckContract = precheck
+	defer func() { parsedLog.Request.PrecheckContract = common.Address{} }()
+
+	_, err := validator.ValidateLog(parsedLog)
+
+	var vErr *ValidationError
+	assert.Error(t, err)
+	assert.False(t, errors.As(err, &vErr))
+}
+
+func TestValidateLog_StoresSimulation(t *testing.T) {
+	validator := newTestValidator()
+
+	result, err := validator.ValidateLog(parsedLog)
+
+	assert.NoError(t, err)
+	assert.Equal(t, simulated, result.Simulation)
+}
+
+func TestValidateLog_SimulationReverts(t *testing.T) {
+	engineMock := new(EngineMock)
+	engineMock.On("Evaluate", mock.Anything, mock.Anything).Return(&profitability.Estimate{Required: big.NewInt(1)}, nil)
+	validator := newValidator(srcChain, networksCfg.Networks, oracle, engineMock, newSimulatorMock(&simulation.Result{RevertReason: "call failed"}, nil), nil, nil, dial)
+
+	_, err := validator.ValidateLog(parsedLog)
+
+	var vErr *ValidationError
+	assert.ErrorIs(t, err, ErrSimulationReverted)
+	assert.True(t, errors.As(err, &vErr))
+	assert.Equal(t, "call failed", vErr.Actual)
+}
+
+func TestValidateLog_SimulationError(t *testing.T) {
+	engineMock := new(EngineMock)
+	engineMock.On("Evaluate", mock.Anything, mock.Anything).Return(&profitability.Estimate{Required: big.NewInt(1)}, nil)
+	validator := newValidator(srcChain, networksCfg.Networks, oracle, engineMock, newSimulatorMock(nil, errors.New("rpc error")), nil, nil, dial)
+
+	_, err := validator.ValidateLog(parsedLog)
+
+	var vErr *ValidationError
+	assert.Error(t, err)
+	assert.False(t, errors.As(err, &vErr))
+}
+
+func newPolicyValidator(policy chains.CallPolicy) *ValidatorChain {
+	engineMock := new(EngineMock)
+	engineMock.On("Evaluate", mock.Anything, mock.Anything).Return(&profitability.Estimate{Required: big.NewInt(1)}, nil)
+
+	networks := chains.Networks{}
+	for id, cfg := range networksCfg.Networks {
+		if id == "84532" {
+			cfg.CallPolicy = policy
+		}
+		networks[id] = cfg
+	}
+
+	return newValidator(srcChain, networks, oracle, engineMock, simulator, nil, nil, dial)
+}
+
+func TestValidateLog_CallPolicyAllows(t *testing.T) {
+	validator := newPolicyValidator(chains.CallPolicy{
+		MaxCalls:     1,
+		MaxCallValue: big.NewInt(1000000000000000000),
+		Allow:        []chains.CallRule{{Target: common.HexToAddress("0x1234567890123456789012345678901234567890")}},
+	})
+
+	_, err := validator.ValidateLog(parsedLog)
+
+	assert.NoError(t, err)
+}
+
+func TestValidateLog_TooManyCalls(t *testing.T) {
+	validator := newPolicyValidator(chains.CallPolicy{MaxCalls: 1})
+
+	prevCalls := parsedLog.Request.Calls
+	parsedLog.Request.Calls = append([]bindings.Call{{To: common.HexToAddress("0x1"), Value: big.NewInt(0)}}, prevCalls...)
+	defer func() { parsedLog.Request.Calls = prevCalls }()
+
+	_, err := validator.ValidateLog(parsedLog)
+
+	assert.ErrorIs(t, err, ErrTooManyCalls)
+}
+
+func TestValidateLog_CallValueTooHigh(t *testing.T) {
+	validator := newPolicyValidator(chains.CallPolicy{MaxCallValue: big.NewInt(999999999999999999)})
+
+	_, err := validator.ValidateLog(parsedLog)
+
+	assert.ErrorIs(t, err, ErrCallValueTooHigh)
+}
+
+func TestValidateLog_CallDenied(t *testing.T) {
+	validator := newPolicyValidator(chains.CallPolicy{
+		Deny: []chains.CallRule{{Target: common.HexToAddress("0x1234567890123456789012345678901234567890")}},
+	})
+
+	_, err := validator.ValidateLog(parsedLog)
+
+	var vErr *ValidationError
+	assert.ErrorIs(t, err, ErrCallNotPermitted)
+	assert.True(t, errors.As(err, &vErr))
+	assert.Equal(t, "call 0 to 0x1234567890123456789012345678901234567890", vErr.Actual)
+}
+
+func TestValidateLog_CallNotAllowed(t *testing.T) {
+	validator := newPolicyValidator(chains.CallPolicy{
+		Allow: []chains.CallRule{{Target: common.HexToAddress("0x036CbD53842c5426634e7929541eC2318f3dCF7e")}},
+	})
+
+	_, err := validator.ValidateLog(parsedLog)
+
+	assert.ErrorIs(t, err, ErrCallNotPermitted)
+}
+
+func TestValidateLog_RequesterBlocked(t *testing.T) {
+	engineMock := new(EngineMock)
+	guard := requesters.NewGuard(requesters.NewLists(requesters.ListsConfig{Blocklist: []common.Address{parsedLog.Request.Requester}}), nil)
+	validator := newValidator(srcChain, networksCfg.Networks, oracle, engineMock, simulator, guard, nil, dial)
+
+	_, err := validator.ValidateLog(parsedLog)
+
+	assert.ErrorIs(t, err, ErrRequesterBlocked)
+	engineMock.AssertNotCalled(t, "Evaluate", mock.Anything, mock.Anything)
+}
+
+func TestValidateLog_RateLimited(t *testing.T) {
+	engineMock := new(EngineMock)
+	engineMock.On("Evaluate", mock.Anything, mock.Anything).Return(&profitability.Estimate{Required: big.NewInt(1)}, nil)
+	guard := requesters.NewGuard(nil, requesters.NewLimiter([]requesters.Limit{{Max: 1, Window: time.Minute}}))
+	validator := newValidator(srcChain, networksCfg.Networks, oracle, engineMock, simulator, guard, nil, dial)
+
+	_, err := validator.ValidateLog(parsedLog)
+	assert.NoError(t, err)
+
+	// the same request seen again isn't counted twice
+	_, err = validator.ValidateLog(parsedLog)
+	assert.NoError(t, err)
+
+	second := *parsedLog
+	second.RequestHash = [32]byte{2}
+	_, err = validator.ValidateLog(&second)
+	assert.ErrorIs(t, err, ErrRateLimited)
+}
+
+func TestValidateLog_PolicyViolation(t *testing.T) {
+	engineMock := new(EngineMock)
+	engineMock.On("Evaluate", mock.Anything, mock.Anything).Return(&profitability.Estimate{Cost: big.NewInt(1_000_000_000_000_000_000), Required: big.NewInt(1)}, nil)
+	rules, _ := policy.NewEngine(policy.Config{Routes: []policy.Route{{Name: "arbitrum-to-base", Source: "421614", Destination: "84532", MinMarginBps: 10_001}}})
+	validator := newValidator(srcChain, networksCfg.Networks, oracle, engineMock, simulator, nil, rules, dial)
+
+	_, err := validator.ValidateLog(parsedLog)
+
+	assert.ErrorIs(t, err, ErrPolicyMargin)
+	assert.Equal(t, "arbitrum-to-base", err.(*ValidationError).Expected)
+}
+
+var (
+	hashiProver = common.HexToAddress("0x5555555555555555555555555555555555555555")
+	shoyuBashi  = common.HexToAddress("0x6666666666666666666666666666666666666666")
+)
+
+// hashiRoute returns a source chain that doesn't expose L1 state, so its
+// requests to Base Sepolia are proven through Hashi, and such a request as the
+// listener decodes it.
+func hashiRoute() (*chains.ChainConfig, bindings.CrossChainRequest) {
+	no := false
+	src := *srcChain
+	src.ExposesL1State = &no
+	src.ShoyuBashi = shoyuBashi
+	src.ProverContracts = map[string]common.Address{string(provers.HashiProver): hashiProver}
+
+	request := parsedLog.Request
+	request.ProverContract = hashiProver
+	request.L2Oracle = common.Address{}
+
+	return &src, request
+}
+
+func newHashiValidator(src *chains.ChainConfig) *ValidatorChain {
+	engineMock := new(EngineMock)
+	engineMock.On("Evaluate", mock.Anything, mock.Anything).Return(&profitability.Estimate{Required: big.NewInt(1)}, nil)
+
+	return newValidator(src, networksCfg.Networks, oracle, engineMock, simulator, nil, nil, dial)
+}
+
+func TestValidateLog_HashiRoute(t *testing.T) {
+	src, request := hashiRoute()
+
+	result, err := newHashiValidator(src).ValidateLog(decodeLog(t, request))
+
+	assert.NoError(t, err)
+	assert.Equal(t, ids.EVMChain(request.DestinationChainId), result.Destination)
+}
+
+func TestValidateLog_HashiRouteMissingShoyuBashi(t *testing.T) {
+	src, request := hashiRoute()
+	src.ShoyuBashi = common.Address{}
+
+	_, err := newHashiValidator(src).ValidateLog(decodeLog(t, request))
+
+	assert.ErrorIs(t, err, ErrMissingShoyuBashi)
+}
+
+func TestValidateLog_HashiRouteWithL2Oracle(t *testing.T) {
+	src, request := hashiRoute()
+	request.L2Oracle = networksCfg.Networks["84532"].L2Oracle
+
+	_, err := newHashiValidator(src).ValidateLog(decodeLog(t, request))
+
+	assert.ErrorIs(t, err, ErrUnknownL2Oracle)
+}
+
+func TestValidateLog_HashiProverNotConfigured(t *testing.T) {
+	src, request := hashiRoute()
+	src.ProverContracts = srcChain.ProverContracts
+
+	_, err := newHashiValidator(src).ValidateLog(decodeLog(t, request))
+
+	assert.ErrorIs(t, err, ErrProverNotConfigured)
+}
+
+func TestValidateLog_HashiRouteUnknownProver(t *testing.T) {
+	src, request := hashiRoute()
+	request.ProverContract = srcChain.ProverContracts["OPStack"]
+
+	_, err := newHashiValidator(src).ValidateLog(decodeLog(t, request))
+
+	assert.ErrorIs(t, err, ErrUnknownProver)
+}
+
+// RIP-7755 requests can't carry attributes, but a request that names its
+// ShoyuBashi, as RRC-7755 ones do, must name the trusted one.
+func TestValidateContracts_HashiRouteNamedShoyuBashi(t *testing.T) {
+	src, request := hashiRoute()
+	log := decodeLog(t, request)
+	dst, _ := networksCfg.Networks.GetChainConfig(request.DestinationChainId)
+	v := &validator{srcChain: src}
+
+	for _, tc := range []struct {
+		shoyuBashi common.Address
+		expected   error
+	}{
+		{shoyuBashi, nil},
+		{common.HexToAddress("0x7"), ErrUnknownShoyuBashi},
+	} {
+		named := ids.AddressToBytes32(tc.shoyuBashi)
+		attrs := append(attributes.FromRequest(&log.Request), append(attributes.ShoyuBashiSelector[:], named[:]...))
+		req := &Request{Log: log, SrcChain: src, DstChain: dst, Prover: src.SelectProver(dst), Attributes: attrs, Result: &Result{}}
+
+		err := v.validateContracts(context.Background(), req)
+
+		if tc.expected == nil {
+			assert.NoError(t, err)
+		} else {
+			assert.ErrorIs(t, err, tc.expected)
+		}
+	}
 }
@@ -0,0 +1,71 @@
+package watch
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"time"
+
+	logger "github.com/ethereum/go-ethereum/log"
+	"github.com/fsnotify/fsnotify"
+)
+
+// Delay is how long the files must stay unchanged before changed is called.
+const Delay = 100 * time.Millisecond
+
+// Files calls changed whenever one of the files at paths changes or the process
+// receives one of signals, until ctx is done. Editors and deploy tools often
+// replace files rather than write them, so the files' directories are watched.
+// Calls to changed never overlap.
+func Files(ctx context.Context, paths []string, changed func(), signals ...os.Signal) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	watched := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		watched[filepath.Clean(path)] = true
+		if err := watcher.Add(filepath.Dir(path)); err != nil {
+			watcher.Close()
+			return err
+		}
+	}
+
+	sig := make(chan os.Signal, 1)
+	if len(signals) > 0 {
+		signal.Notify(sig, signals...)
+	}
+
+	go func() {
+		defer watcher.Close()
+		defer signal.Stop(sig)
+
+		// Writes usually arrive as several events, starting with a truncate,
+		// so only call changed once the files have settled.
+		settled := time.NewTimer(0)
+		<-settled.C
+		defer settled.Stop()
+
+		for {
+			select {
+			case event := <-watcher.Events:
+				if !watched[filepath.Clean(event.Name)] || event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+					continue
+				}
+				settled.Reset(Delay)
+			case <-settled.C:
+				changed()
+			case <-sig:
+				changed()
+			case err := <-watcher.Errors:
+				logger.Error("File watcher error", "paths", paths, "error", err)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "networks.yaml")
+	os.WriteFile(path, []byte("networks: {}\n"), 0o644)
+
+	changes := make(chan struct{}, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	assert.NoError(t, Files(ctx, []string{path}, func() { changes <- struct{}{} }, syscall.SIGHUP))
+
+	// unrelated files in the same directory are ignored
+	os.WriteFile(filepath.Join(dir, "other.yaml"), []byte("x"), 0o644)
+	time.Sleep(3 * Delay)
+	assert.Empty(t, changes)
+
+	// several writes settle into one call
+	os.WriteFile(path, []byte(""), 0o644)
+	os.WriteFile(path, []byte("networks: {}\n"), 0o644)
+	select {
+	case <-changes:
+	case <-time.After(2 * time.Second):
+		t.Fatal("no call after the file changed")
+	}
+	time.Sleep(3 * Delay)
+	assert.Empty(t, changes)
+
+	// replacing the file is noticed
+	replacement := filepath.Join(dir, "networks.yaml.tmp")
+	os.WriteFile(replacement, []byte("networks: {}\n"), 0o644)
+	os.Rename(replacement, path)
+	select {
+	case <-changes:
+	case <-time.After(2 * time.Second):
+		t.Fatal("no call after the file was replaced")
+	}
+
+	syscall.Kill(syscall.Getpid(), syscall.SIGHUP)
+	select {
+	case <-changes:
+	case <-time.After(2 * time.Second):
+		t.Fatal("no call on SIGHUP")
+	}
+}
+
+func TestFilesMissingDirectory(t *testing.T) {
+	err := Files(context.Background(), []string{filepath.Join(t.TempDir(), "missing", "networks.yaml")}, func() {})
+
+	assert.Error(t, err)
+}